@@ -0,0 +1,210 @@
+package ctxi18n
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"sync/atomic"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+// Bundle contains a set of locales along with the rules used to select
+// them, so that multiple independent catalogs may be used in the same
+// application or in parallel tests without relying on any globals.
+type Bundle struct {
+	locales       atomic.Pointer[i18n.Locales]
+	defaultLocale i18n.Code
+	defaultFunc   func() i18n.Code
+	fallbacks     []i18n.Code
+	missingKey    i18n.MissingKeyHandler
+}
+
+// Option is used to configure a new Bundle.
+type Option func(*Bundle)
+
+// WithDefaultLocale sets the locale to use when none of the requested
+// locales or fallbacks are available. Defaults to "en".
+func WithDefaultLocale(code i18n.Code) Option {
+	return func(b *Bundle) {
+		b.defaultLocale = code
+	}
+}
+
+// WithFallbacks defines an ordered list of locales to try when none of
+// the requested locales are available, before using the default.
+func WithFallbacks(codes ...i18n.Code) Option {
+	return func(b *Bundle) {
+		b.fallbacks = append(b.fallbacks, codes...)
+	}
+}
+
+// WithMissingKeyHandler sets the handler that will be added to the context
+// by `WithLocale` and called whenever a translation is missing.
+func WithMissingKeyHandler(h i18n.MissingKeyHandler) Option {
+	return func(b *Bundle) {
+		b.missingKey = h
+	}
+}
+
+// NewBundle prepares a new Bundle with an empty set of locales.
+func NewBundle(opts ...Option) *Bundle {
+	b := &Bundle{
+		defaultLocale: "en",
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	b.locales.Store(new(i18n.Locales))
+	return b
+}
+
+// Locales provides the current set of locales in the bundle.
+func (b *Bundle) Locales() *i18n.Locales {
+	return b.locales.Load()
+}
+
+// Load walks through all the files in provided File System and merges
+// them with the bundle's locales.
+func (b *Bundle) Load(fs fs.FS, opts ...i18n.LoadOption) error {
+	return b.locales.Load().Load(fs, opts...)
+}
+
+// LoadWithDefault performs the regular load operation, but will merge
+// the default locale with every other locale, ensuring that every text
+// has at least the value from the default locale.
+func (b *Bundle) LoadWithDefault(fs fs.FS, locale i18n.Code, opts ...i18n.LoadOption) error {
+	return b.locales.Load().LoadWithDefault(fs, locale, opts...)
+}
+
+// LoadSnapshot loads the locales from a binary snapshot prepared with
+// the `MarshalBinary` method of `i18n.Locales`.
+func (b *Bundle) LoadSnapshot(data []byte) error {
+	return b.locales.Load().UnmarshalBinary(data)
+}
+
+// LoadLayers replaces the bundle's layers with the provided ordered
+// list, from lowest to highest precedence.
+func (b *Bundle) LoadLayers(layers ...i18n.Layer) error {
+	return b.locales.Load().LoadLayers(layers...)
+}
+
+// ReplaceLayer reloads the layer with the same name in the bundle.
+func (b *Bundle) ReplaceLayer(layer i18n.Layer) error {
+	return b.locales.Load().ReplaceLayer(layer)
+}
+
+// Watch loads the locales from the provided File System and starts a
+// background process that will reload them whenever the files change, until
+// the context is cancelled. Each reload replaces the bundle's locales in
+// one go.
+func (b *Bundle) Watch(ctx context.Context, src fs.FS, opts ...i18n.ReloaderOption) error {
+	opts = append(opts, i18n.OnReload(func(ls *i18n.Locales) {
+		b.locales.Store(ls)
+	}))
+	r, err := i18n.NewReloader(src, opts...)
+	if err != nil {
+		return err
+	}
+	b.locales.Store(r.Locales())
+	go r.Watch(ctx)
+	return nil
+}
+
+// Get provides the Locale object for the matching code.
+func (b *Bundle) Get(code i18n.Code) *i18n.Locale {
+	return b.locales.Load().Get(code)
+}
+
+// Match attempts to find the best possible matching locale based on the
+// locale string provided. The locale string is parsed according to the
+// "Accept-Language" header format defined in RFC9110.
+func (b *Bundle) Match(locale string) *i18n.Locale {
+	return b.locales.Load().Match(locale)
+}
+
+// WithLocale tries to match the provided code with a locale, followed
+// by the fallbacks and default locale, and ensures it is available
+// inside the context along with the default locale and the missing key
+// handler, if defined.
+func (b *Bundle) WithLocale(ctx context.Context, locale string) (context.Context, error) {
+	return b.withLocale(ctx, locale)
+}
+
+// WithEnvLocale adds the locale that best matches the POSIX environment
+// variables, like `LANG`, to the context, as used by command line tools.
+// The fallbacks and default locale are used if there is no match.
+func (b *Bundle) WithEnvLocale(ctx context.Context) (context.Context, error) {
+	return b.withLocale(ctx, envLocale())
+}
+
+func envLocale() string {
+	codes := i18n.EnvCodes()
+	list := make([]string, len(codes))
+	for i, c := range codes {
+		list[i] = c.String()
+	}
+	return strings.Join(list, ",")
+}
+
+// defaultCode provides the code of the default locale, which the package
+// level bundle reads from the DefaultLocale variable each time.
+func (b *Bundle) defaultCode() i18n.Code {
+	if b.defaultFunc != nil {
+		return b.defaultFunc()
+	}
+	return b.defaultLocale
+}
+
+func (b *Bundle) withLocale(ctx context.Context, locale string) (context.Context, error) {
+	ls := b.locales.Load()
+	l := ls.Match(locale)
+	for _, c := range b.fallbacks {
+		if l != nil {
+			break
+		}
+		l = ls.Get(c)
+	}
+	dl := ls.Get(b.defaultCode())
+	if l == nil {
+		l = dl
+		if l == nil {
+			return nil, ErrMissingLocale
+		}
+	}
+	if dl != nil {
+		ctx = i18n.WithDefaultLocale(ctx, dl)
+	}
+	if b.missingKey != nil {
+		ctx = i18n.WithMissingKeyHandler(ctx, b.missingKey)
+	}
+	return l.WithContext(ctx), nil
+}
+
+// NewError prepares an `i18n.Error` like `i18n.NewError`, whose Error
+// method will render the message in the bundle's default locale.
+func (b *Bundle) NewError(key string, args i18n.M) *i18n.Error {
+	return b.WrapError(nil, key, args)
+}
+
+// WrapError prepares an `i18n.Error` with the cause like `i18n.WrapError`,
+// whose Error method will render the message in the bundle's default
+// locale.
+func (b *Bundle) WrapError(err error, key string, args i18n.M) *i18n.Error {
+	e := i18n.WrapError(err, key, args)
+	e.Default = b.Get(b.defaultCode())
+	return e
+}
+
+// Error renders the message of the error in the bundle's default locale
+// if it is, or wraps, an `i18n.Error`, or provides the regular error
+// message otherwise. An empty string is provided for a nil error.
+func (b *Bundle) Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	if l := b.Get(b.defaultCode()); l != nil {
+		return l.Error(err)
+	}
+	return err.Error()
+}
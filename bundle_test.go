@@ -0,0 +1,150 @@
+package ctxi18n_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"testing/fstest"
+
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundle(t *testing.T) {
+	t.Parallel()
+	b := ctxi18n.NewBundle()
+	require.NoError(t, b.Load(examples.Content))
+
+	l := b.Get("es")
+	require.NotNil(t, l)
+	assert.Equal(t, "Iniciar Sesión", l.T("login.button"))
+	assert.Equal(t, "en", b.Match("en-US,en;q=0.9").Code().String())
+	assert.Len(t, b.Locales().Codes(), 2)
+
+	ctx, err := b.WithLocale(context.Background(), "inv")
+	require.NoError(t, err)
+	assert.Equal(t, "en", i18n.GetLocale(ctx).Code().String())
+
+	other := ctxi18n.NewBundle()
+	require.NoError(t, other.Load(fstest.MapFS{
+		"fr.yaml": {Data: []byte("fr:\n  login:\n    button: \"Connexion\"\n")},
+	}))
+	assert.Nil(t, other.Get("es"), "bundles are independent")
+	assert.Nil(t, b.Get("fr"))
+}
+
+func TestBundleOptions(t *testing.T) {
+	t.Parallel()
+	src := fstest.MapFS{
+		"all.yaml": {Data: []byte("es:\n  foo: \"es\"\nfr:\n  foo: \"fr\"\nde:\n  foo: \"de\"\n")},
+	}
+
+	b := ctxi18n.NewBundle(ctxi18n.WithDefaultLocale("de"))
+	require.NoError(t, b.Load(src))
+	ctx, err := b.WithLocale(context.Background(), "en")
+	require.NoError(t, err)
+	assert.Equal(t, "de", i18n.T(ctx, "foo"))
+
+	b = ctxi18n.NewBundle(
+		ctxi18n.WithDefaultLocale("de"),
+		ctxi18n.WithFallbacks("it", "fr"),
+	)
+	require.NoError(t, b.Load(src))
+	ctx, err = b.WithLocale(context.Background(), "en")
+	require.NoError(t, err)
+	assert.Equal(t, "fr", i18n.T(ctx, "foo"))
+
+	ctx, err = b.WithLocale(context.Background(), "en,es")
+	require.NoError(t, err)
+	assert.Equal(t, "es", i18n.T(ctx, "foo"), "requested locale first")
+
+	b = ctxi18n.NewBundle()
+	require.NoError(t, b.Load(src))
+	_, err = b.WithLocale(context.Background(), "it")
+	assert.ErrorIs(t, err, ctxi18n.ErrMissingLocale)
+}
+
+func TestBundleDefaultLocale(t *testing.T) {
+	t.Parallel()
+	src := fstest.MapFS{
+		"all.yaml": {Data: []byte("es:\n  denied: \"Acceso denegado\"\nfr:\n  other: \"Autre\"\n")},
+	}
+	b := ctxi18n.NewBundle(ctxi18n.WithDefaultLocale("es"))
+	require.NoError(t, b.Load(src))
+
+	err := i18n.NewError("denied", nil)
+	assert.Equal(t, "Acceso denegado", b.Error(fmt.Errorf("checking: %w", err)))
+	assert.Equal(t, "plain", b.Error(errors.New("plain")))
+
+	ctx, cerr := b.WithLocale(context.Background(), "fr")
+	require.NoError(t, cerr)
+	assert.Equal(t, "fr", i18n.GetLocale(ctx).Code().String())
+	assert.Equal(t, "es", i18n.GetDefaultLocale(ctx).Code().String())
+	assert.Equal(t, "Acceso denegado", err.Localized(ctx).Message)
+	assert.Equal(t, "Zapatos", i18n.String{"es": "Zapatos", "en": "Shoes"}.Localize(ctx))
+
+	assert.Equal(t, "denied", ctxi18n.NewBundle().Error(err), "no default locale")
+	assert.Equal(t, "", b.Error(nil))
+
+	be := b.WrapError(errors.New("db"), "denied", nil)
+	assert.Equal(t, "Acceso denegado: db", be.Error())
+	assert.Equal(t, "Acceso denegado", b.NewError("denied", nil).Error())
+	assert.ErrorIs(t, be, err)
+}
+
+func TestDefaultBundle(t *testing.T) {
+	require.NoError(t, ctxi18n.Load(examples.Content))
+	assert.Equal(t, ctxi18n.Get("en"), ctxi18n.Default().Get("en"))
+}
+
+func TestBundleMissingKeyHandler(t *testing.T) {
+	t.Parallel()
+	var missing []string
+	b := ctxi18n.NewBundle(ctxi18n.WithMissingKeyHandler(
+		func(_ context.Context, code i18n.Code, key string, _ ...any) (string, bool) {
+			missing = append(missing, code.String()+":"+key)
+			return "", false
+		},
+	))
+	require.NoError(t, b.Load(examples.Content))
+	ctx, err := b.WithLocale(context.Background(), "es")
+	require.NoError(t, err)
+	assert.Equal(t, "Iniciar Sesión", i18n.T(ctx, "login.button"))
+	assert.Equal(t, "!(MISSING: bad.key)", i18n.T(ctx, "bad.key"))
+	assert.Equal(t, []string{"es:bad.key"}, missing)
+}
+
+func TestBundleWithEnvLocale(t *testing.T) {
+	b := newTestBundle(t)
+	t.Setenv("LANGUAGE", "")
+	t.Setenv("LC_ALL", "")
+	t.Setenv("LC_MESSAGES", "")
+
+	t.Setenv("LANG", "es_MX.UTF-8")
+	ctx, err := b.WithEnvLocale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "es", ctxi18n.Locale(ctx).Code().String())
+
+	t.Setenv("LANG", "C")
+	ctx, err = b.WithEnvLocale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "en", ctxi18n.Locale(ctx).Code().String())
+
+	t.Setenv("LANG", "fr_FR.UTF-8")
+	t.Setenv("LANGUAGE", "de:es")
+	require.NoError(t, ctxi18n.Load(examples.Content))
+	ctx, err = ctxi18n.WithEnvLocale(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "es", ctxi18n.Locale(ctx).Code().String())
+}
+
+func newTestBundle(t *testing.T) *ctxi18n.Bundle {
+	t.Helper()
+	b := ctxi18n.NewBundle()
+	require.NoError(t, b.Load(examples.Content))
+	return b
+}
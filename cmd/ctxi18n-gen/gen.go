@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+const countParam = "count"
+
+var (
+	placeholderRegexp = regexp.MustCompile(`%\{([^}]+)\}`)
+	pluralKeys        = map[string]bool{
+		"zero": true, "one": true, "two": true, "few": true, "many": true, "other": true,
+	}
+)
+
+// generator collects the keys from one or more dictionaries and prepares
+// the Go source code to access them.
+type generator struct {
+	pkg     string
+	entries map[string]*entry
+}
+
+// entry describes a single key found in the dictionaries.
+type entry struct {
+	key    string
+	plural bool
+	params map[string]bool
+}
+
+func newGenerator(pkg string) *generator {
+	return &generator{
+		pkg:     pkg,
+		entries: make(map[string]*entry),
+	}
+}
+
+// add includes all the keys from the dictionary.
+func (g *generator) add(d *i18n.Dict) {
+	g.walk("", d)
+}
+
+func (g *generator) walk(prefix string, d *i18n.Dict) {
+	for _, k := range d.Keys() {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		v := d.Get(k)
+		switch {
+		case isPlural(v):
+			e := g.entry(key)
+			e.plural = true
+			for _, f := range v.Keys() {
+				e.addParams(v.Get(f).Value())
+			}
+		case v.Keys() != nil:
+			g.walk(key, v)
+		default:
+			g.entry(key).addParams(v.Value())
+		}
+	}
+}
+
+func (g *generator) entry(key string) *entry {
+	e, ok := g.entries[key]
+	if !ok {
+		e = &entry{key: key, params: make(map[string]bool)}
+		g.entries[key] = e
+	}
+	return e
+}
+
+func (e *entry) addParams(txt string) {
+	for _, m := range placeholderRegexp.FindAllStringSubmatch(txt, -1) {
+		e.params[m[1]] = true
+	}
+}
+
+// isPlural checks to see if the dictionary only contains plural forms.
+func isPlural(d *i18n.Dict) bool {
+	keys := d.Keys()
+	if keys == nil || d.Get("other") == nil {
+		return false
+	}
+	for _, k := range keys {
+		if !pluralKeys[k] || d.Get(k).Keys() != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// generate prepares the formatted Go source code.
+func (g *generator) generate() ([]byte, error) {
+	keys := make([]string, 0, len(g.entries))
+	for k := range g.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	names := make(map[string]string)
+	funcs := false
+	for _, k := range keys {
+		e := g.entries[k]
+		name := goName(k)
+		if prev, ok := names[name]; ok {
+			return nil, fmt.Errorf("keys '%s' and '%s' both generate the name %s", prev, k, name)
+		}
+		names[name] = k
+		if e.plural || len(e.params) > 0 {
+			funcs = true
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "// Code generated by ctxi18n-gen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(buf, "package %s\n\n", g.pkg)
+	if funcs {
+		fmt.Fprintf(buf, "import (\n\t\"context\"\n\n\t\"github.com/invopop/ctxi18n/i18n\"\n)\n\n")
+	}
+	for _, k := range keys {
+		if err := g.entries[k].write(buf); err != nil {
+			return nil, err
+		}
+	}
+
+	out, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting source: %w", err)
+	}
+	return out, nil
+}
+
+func (e *entry) write(buf *bytes.Buffer) error {
+	name := goName(e.key)
+	params := make([]string, 0, len(e.params))
+	for p := range e.params {
+		if e.plural && p == countParam {
+			continue
+		}
+		params = append(params, p)
+	}
+	sort.Strings(params)
+
+	if !e.plural && len(params) == 0 {
+		fmt.Fprintf(buf, "// %s is the %q key.\nconst %s = %q\n\n", name, e.key, name, e.key)
+		return nil
+	}
+
+	args := make([]string, 0, len(params)+1)
+	vals := make([]string, 0, len(params)+1)
+	if e.plural {
+		args = append(args, countParam+" int")
+		if e.params[countParam] {
+			vals = append(vals, fmt.Sprintf("%q: %s", countParam, countParam))
+		}
+	}
+	names := make(map[string]string)
+	for _, p := range params {
+		v := goParam(p)
+		if prev, ok := names[v]; ok {
+			return fmt.Errorf("key '%s': placeholders '%s' and '%s' both generate the parameter %s", e.key, prev, p, v)
+		}
+		names[v] = p
+		args = append(args, v+" any")
+		vals = append(vals, fmt.Sprintf("%q: %s", p, v))
+	}
+	m := ""
+	if len(vals) > 0 {
+		m = ", i18n.M{" + strings.Join(vals, ", ") + "}"
+	}
+
+	if e.plural {
+		fmt.Fprintf(buf, "// %s translates the %q key pluralized using the count.\n", name, e.key)
+		fmt.Fprintf(buf, "func %s(ctx context.Context, %s) string {\n", name, strings.Join(args, ", "))
+		fmt.Fprintf(buf, "\treturn i18n.N(ctx, %q, %s%s)\n}\n\n", e.key, countParam, m)
+		return nil
+	}
+	fmt.Fprintf(buf, "// %s translates the %q key.\n", name, e.key)
+	fmt.Fprintf(buf, "func %s(ctx context.Context, %s) string {\n", name, strings.Join(args, ", "))
+	fmt.Fprintf(buf, "\treturn i18n.T(ctx, %q%s)\n}\n\n", e.key, m)
+	return nil
+}
+
+// goName converts a key into an exported Go identifier, so that
+// `login.signup-button` becomes `LoginSignupButton`.
+func goName(key string) string {
+	out := camelCase(key, true)
+	if r := []rune(out); len(r) == 0 || !unicode.IsUpper(r[0]) {
+		out = "K" + out
+	}
+	return out
+}
+
+// goParam converts a placeholder name into a Go parameter name.
+func goParam(name string) string {
+	out := camelCase(name, false)
+	if out == "" || unicode.IsDigit(rune(out[0])) {
+		out = "p" + out
+	}
+	if token.IsKeyword(out) || out == "ctx" || out == countParam || out == "i18n" || out == "context" {
+		out = out + "Val"
+	}
+	return out
+}
+
+func camelCase(s string, upper bool) string {
+	parts := strings.FieldsFunc(s, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	var b strings.Builder
+	for i, p := range parts {
+		r := []rune(p)
+		if i > 0 || upper {
+			r[0] = unicode.ToUpper(r[0])
+		} else {
+			r[0] = unicode.ToLower(r[0])
+		}
+		b.WriteString(string(r))
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestRun(t *testing.T) {
+	out := new(bytes.Buffer)
+	err := run([]string{"-src", "testdata/locales", "-pkg", "keys"}, out)
+	require.NoError(t, err)
+
+	golden := filepath.Join("testdata", "keys.golden")
+	if *update {
+		require.NoError(t, os.WriteFile(golden, out.Bytes(), 0o644))
+	}
+	data, err := os.ReadFile(golden)
+	require.NoError(t, err)
+	assert.Equal(t, string(data), out.String())
+
+	t.Run("single locale", func(t *testing.T) {
+		out := new(bytes.Buffer)
+		err := run([]string{"-src", "testdata/locales", "-pkg", "keys", "-locale", "es"}, out)
+		require.NoError(t, err)
+		assert.Contains(t, out.String(), "func WelcomeHello(ctx context.Context, name any, place any) string {")
+		assert.NotContains(t, out.String(), "InboxEmails")
+	})
+
+	t.Run("output file", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "keys", "keys.go")
+		err := run([]string{"-src", "testdata/locales", "-out", path}, nil)
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "package keys\n")
+	})
+
+	t.Run("snapshot", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "catalog.bin")
+		err := run([]string{"-src", "testdata/locales", "-snapshot", path}, nil)
+		require.NoError(t, err)
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.UnmarshalBinary(data))
+		assert.Equal(t, "Log In", ls.Get("en").T("login.button"))
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		err := run([]string{"-src", "testdata/locales"}, nil)
+		assert.ErrorContains(t, err, "package name required")
+		err = run([]string{"-src", "testdata/missing", "-pkg", "keys"}, nil)
+		assert.ErrorContains(t, err, "walking directory")
+		err = run([]string{"-src", "testdata/locales", "-pkg", "keys", "-locale", "fr"}, nil)
+		assert.ErrorContains(t, err, "undefined locale: fr")
+		err = run([]string{"-bad"}, new(bytes.Buffer))
+		assert.ErrorContains(t, err, "flag provided but not defined")
+	})
+}
+
+func TestGeneratorConflicts(t *testing.T) {
+	d := i18n.NewDict()
+	d.Add("login_button", "Log In")
+	d.Add("login", map[string]any{"button": "Log In"})
+	g := newGenerator("keys")
+	g.add(d)
+	_, err := g.generate()
+	assert.ErrorContains(t, err, "keys 'login.button' and 'login_button' both generate the name LoginButton")
+}
+
+func TestGeneratorParamConflicts(t *testing.T) {
+	d := i18n.NewDict()
+	d.Add("greet", "Hi %{first_name} %{firstName}")
+	g := newGenerator("keys")
+	g.add(d)
+	_, err := g.generate()
+	assert.EqualError(t, err, "key 'greet': placeholders 'firstName' and 'first_name' both generate the parameter firstName")
+
+	d = i18n.NewDict()
+	d.Add("item", map[string]any{"one": "One %{type}", "other": "%{count} %{typeVal}"})
+	g = newGenerator("keys")
+	g.add(d)
+	_, err = g.generate()
+	assert.EqualError(t, err, "key 'item': placeholders 'type' and 'typeVal' both generate the parameter typeVal")
+}
+
+func TestGeneratorConstantsOnly(t *testing.T) {
+	d := i18n.NewDict()
+	d.Add("foo", "bar")
+	g := newGenerator("keys")
+	g.add(d)
+	out, err := g.generate()
+	require.NoError(t, err)
+	assert.NotContains(t, string(out), "import")
+	assert.Contains(t, string(out), "const Foo = \"foo\"")
+}
+
+func TestGoParam(t *testing.T) {
+	assert.Equal(t, "name", goParam("name"))
+	assert.Equal(t, "firstName", goParam("first_name"))
+	assert.Equal(t, "typeVal", goParam("type"))
+	assert.Equal(t, "ctxVal", goParam("ctx"))
+	assert.Equal(t, "p1", goParam("1"))
+}
@@ -0,0 +1,110 @@
+// Command ctxi18n-gen generates a Go package containing a constant or
+// function for every key defined in a catalog of locale files, so that
+// typos are caught by the compiler instead of appearing as missing texts.
+//
+// It is designed to be used with `go generate`, for example:
+//
+//	//go:generate go run github.com/invopop/ctxi18n/cmd/ctxi18n-gen -src ./locales -pkg keys -out keys/keys.go
+//
+// Keys without any `%{...}` placeholders are generated as constants. Keys
+// with placeholders are generated as functions that expect the context and
+// one parameter per placeholder, and pluralized keys as functions that also
+// expect the count.
+//
+// Alternatively, the `-snapshot` flag may be used to write a binary snapshot
+// of the catalog that can be embedded and loaded with `ctxi18n.LoadSnapshot`
+// to avoid parsing the source files on startup.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "ctxi18n-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout io.Writer) error {
+	flags := flag.NewFlagSet("ctxi18n-gen", flag.ContinueOnError)
+	src := flags.String("src", ".", "directory containing the locale files")
+	pkg := flags.String("pkg", "", "name of the generated package, defaults to the output directory name")
+	out := flags.String("out", "", "file to write the generated code to, defaults to stdout")
+	locale := flags.String("locale", "", "only generate keys defined in this locale, defaults to all locales")
+	infer := flags.Bool("infer-locale", false, "determine the locale from file paths")
+	namespace := flags.Bool("namespace-from-file", false, "determine the locale from file paths and use file names as namespaces")
+	snapshot := flags.String("snapshot", "", "write a binary snapshot of the catalog to this file instead of generating code")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	var opts []i18n.LoadOption
+	if *infer {
+		opts = append(opts, i18n.InferLocale())
+	}
+	if *namespace {
+		opts = append(opts, i18n.NamespaceFromFile())
+	}
+	ls := new(i18n.Locales)
+	if err := ls.Load(os.DirFS(*src), opts...); err != nil {
+		return err
+	}
+
+	if *snapshot != "" {
+		data, err := ls.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		return writeFile(*snapshot, data)
+	}
+
+	name := *pkg
+	if name == "" {
+		if *out == "" {
+			return fmt.Errorf("package name required when writing to stdout")
+		}
+		abs, err := filepath.Abs(*out)
+		if err != nil {
+			return err
+		}
+		name = filepath.Base(filepath.Dir(abs))
+	}
+
+	codes := ls.Codes()
+	if *locale != "" {
+		codes = []i18n.Code{i18n.Code(*locale)}
+	}
+	g := newGenerator(name)
+	for _, c := range codes {
+		l := ls.Get(c)
+		if l == nil {
+			return fmt.Errorf("undefined locale: %s", c)
+		}
+		g.add(l.Dict())
+	}
+	data, err := g.generate()
+	if err != nil {
+		return err
+	}
+
+	if *out == "" {
+		_, err = stdout.Write(data)
+		return err
+	}
+	return writeFile(*out, data)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
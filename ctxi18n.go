@@ -4,7 +4,6 @@ package ctxi18n
 
 import (
 	"context"
-	"errors"
 	"io/fs"
 
 	"github.com/invopop/ctxi18n/i18n"
@@ -17,55 +16,112 @@ var (
 )
 
 var (
-	locales *i18n.Locales
+	bundle = newDefaultBundle()
 )
 
 var (
 	// ErrMissingLocale implies that the requested locale was not found
 	// in the current index.
-	ErrMissingLocale = errors.New("locale not defined")
+	ErrMissingLocale = i18n.ErrMissingLocale
 )
 
-func init() {
-	locales = new(i18n.Locales)
+// Default provides the Bundle used by the package level functions, whose
+// default locale is always the current value of DefaultLocale.
+func Default() *Bundle {
+	return bundle
+}
+
+func newDefaultBundle() *Bundle {
+	b := NewBundle()
+	b.defaultFunc = func() i18n.Code {
+		return DefaultLocale
+	}
+	return b
 }
 
 // Load walks through all the files in provided File System and prepares
 // an internal global list of locales ready to use.
-func Load(fs fs.FS) error {
-	return locales.Load(fs)
+func Load(fs fs.FS, opts ...i18n.LoadOption) error {
+	return bundle.Load(fs, opts...)
 }
 
 // LoadWithDefault performs the regular load operation, but will merge
 // the default locale with every other locale, ensuring that every text
 // has at least the value from the default locale.
-func LoadWithDefault(fs fs.FS, locale i18n.Code) error {
-	return locales.LoadWithDefault(fs, locale)
+func LoadWithDefault(fs fs.FS, locale i18n.Code, opts ...i18n.LoadOption) error {
+	return bundle.LoadWithDefault(fs, locale, opts...)
+}
+
+// LoadSnapshot loads the locales from a binary snapshot prepared with
+// the `MarshalBinary` method of `i18n.Locales`, avoiding the need to
+// parse any source files.
+func LoadSnapshot(data []byte) error {
+	return bundle.LoadSnapshot(data)
+}
+
+// LoadLayers replaces the global list of layers with the provided ordered
+// list, from lowest to highest precedence, so that entries from later layers
+// replace those from earlier layers.
+func LoadLayers(layers ...i18n.Layer) error {
+	return bundle.LoadLayers(layers...)
+}
+
+// ReplaceLayer reloads the layer with the same name in the global list.
+func ReplaceLayer(layer i18n.Layer) error {
+	return bundle.ReplaceLayer(layer)
+}
+
+// Watch loads the locales from the provided File System and starts a
+// background process that will reload them whenever the files change, until
+// the context is cancelled. Each reload prepares a completely new set of
+// locales that replaces the global set in one go, so contexts that already
+// contain a locale will continue to use it.
+func Watch(ctx context.Context, src fs.FS, opts ...i18n.ReloaderOption) error {
+	return bundle.Watch(ctx, src, opts...)
 }
 
 // Get provides the Locale object for the matching code.
 func Get(code i18n.Code) *i18n.Locale {
-	return locales.Get(code)
+	return bundle.Get(code)
 }
 
 // Match attempts to find the best possible matching locale based on the
 // locale string provided. The locale string is parsed according to the
 // "Accept-Language" header format defined in RFC9110.
 func Match(locale string) *i18n.Locale {
-	return locales.Match(locale)
+	return bundle.Match(locale)
 }
 
 // WithLocale tries to match the provided code with a locale and ensures
 // it is available inside the context.
 func WithLocale(ctx context.Context, locale string) (context.Context, error) {
-	l := locales.Match(locale)
-	if l == nil {
-		l = locales.Get(DefaultLocale)
-		if l == nil {
-			return nil, ErrMissingLocale
-		}
-	}
-	return l.WithContext(ctx), nil
+	return bundle.WithLocale(ctx, locale)
+}
+
+// WithEnvLocale adds the locale that best matches the POSIX environment
+// variables, like `LANG`, to the context, using the default locale if
+// there is no match.
+func WithEnvLocale(ctx context.Context) (context.Context, error) {
+	return bundle.WithEnvLocale(ctx)
+}
+
+// NewError prepares an `i18n.Error` whose Error method will render the
+// message in the default locale.
+func NewError(key string, args i18n.M) *i18n.Error {
+	return bundle.NewError(key, args)
+}
+
+// WrapError prepares an `i18n.Error` with the cause, whose Error method
+// will render the message in the default locale.
+func WrapError(err error, key string, args i18n.M) *i18n.Error {
+	return bundle.WrapError(err, key, args)
+}
+
+// Error renders the message of the error in the default locale if it is,
+// or wraps, an `i18n.Error`, or provides the regular error message
+// otherwise.
+func Error(err error) string {
+	return bundle.Error(err)
 }
 
 // Locale provides the locale object currently stored in the context.
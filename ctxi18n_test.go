@@ -2,7 +2,12 @@ package ctxi18n_test
 
 import (
 	"context"
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	"github.com/invopop/ctxi18n"
 	"github.com/invopop/ctxi18n/i18n"
@@ -38,6 +43,38 @@ func TestLoadWithDefault(t *testing.T) {
 	assert.Equal(t, "Special Label", l.T("special_label"))
 }
 
+func TestLoadSnapshot(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.Load(examples.Content))
+	data, err := ls.MarshalBinary()
+	require.NoError(t, err)
+
+	require.NoError(t, ctxi18n.LoadSnapshot(data))
+	l := ctxi18n.Get("es")
+	require.NotNil(t, l)
+	assert.Equal(t, "Iniciar Sesión", l.T("login.button"))
+
+	err = ctxi18n.LoadSnapshot(data[:10])
+	assert.ErrorIs(t, err, i18n.ErrSnapshotInvalid)
+}
+
+func TestLoadLayers(t *testing.T) {
+	err := ctxi18n.LoadLayers(
+		i18n.Layer{Name: "embedded", Src: examples.Content},
+		i18n.Layer{Name: "override", Src: fstest.MapFS{
+			"en.yaml": {Data: []byte("en:\n  special_label: \"Override\"\n")},
+		}},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "Override", ctxi18n.Get("en").T("special_label"))
+
+	err = ctxi18n.ReplaceLayer(i18n.Layer{Name: "override", Src: fstest.MapFS{}})
+	require.NoError(t, err)
+	assert.Equal(t, "Special Label", ctxi18n.Get("en").T("special_label"))
+
+	require.NoError(t, ctxi18n.LoadLayers())
+}
+
 func TestGet(t *testing.T) {
 	err := ctxi18n.Load(examples.Content)
 	assert.NoError(t, err)
@@ -84,3 +121,67 @@ func TestWithLocale(t *testing.T) {
 	ctxi18n.DefaultLocale = "es"
 
 }
+
+func TestDefaultBundleLocale(t *testing.T) {
+	require.NoError(t, ctxi18n.Load(examples.Content))
+	prev := ctxi18n.DefaultLocale
+	ctxi18n.DefaultLocale = "es"
+	defer func() { ctxi18n.DefaultLocale = prev }()
+
+	ctx, err := ctxi18n.Default().WithLocale(context.Background(), "inv")
+	require.NoError(t, err)
+	assert.Equal(t, "es", ctxi18n.Locale(ctx).Code().String())
+	assert.Equal(t, "es", i18n.GetDefaultLocale(ctx).Code().String())
+
+	ctxi18n.DefaultLocale = "bad"
+	_, err = ctxi18n.Default().WithLocale(context.Background(), "inv")
+	assert.ErrorIs(t, err, ctxi18n.ErrMissingLocale)
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "en.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("en:\n  foo: \"bar\"\n"), 0o644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	err := ctxi18n.Watch(ctx, os.DirFS(dir), i18n.ReloadEvery(5*time.Millisecond))
+	require.NoError(t, err)
+	l := ctxi18n.Get("en")
+	require.NotNil(t, l)
+	assert.Equal(t, "bar", l.T("foo"))
+
+	require.NoError(t, os.WriteFile(path, []byte("en:\n  foo: \"baz\"\n"), 0o644))
+	mod := time.Now().Add(time.Second)
+	require.NoError(t, os.Chtimes(path, mod, mod))
+	assert.Eventually(t, func() bool {
+		return ctxi18n.Get("en").T("foo") == "baz"
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, "bar", l.T("foo"), "previous locale unchanged")
+
+	err = ctxi18n.Watch(ctx, os.DirFS(filepath.Join(dir, "missing")))
+	assert.ErrorContains(t, err, "walking directory")
+
+	cancel()
+	require.NoError(t, ctxi18n.Load(examples.Content))
+}
+
+func TestErrorDefaultLocale(t *testing.T) {
+	require.NoError(t, ctxi18n.Load(fstest.MapFS{
+		"errors.yaml": {Data: []byte("en:\n  errors:\n    denied: \"Access denied\"\n")},
+	}))
+	prev := ctxi18n.DefaultLocale
+	ctxi18n.DefaultLocale = "en"
+	defer func() { ctxi18n.DefaultLocale = prev }()
+
+	err := i18n.NewError("errors.denied", nil)
+	assert.Equal(t, "errors.denied", err.Error())
+	assert.Equal(t, "Access denied", ctxi18n.Error(err))
+	assert.Equal(t, "", ctxi18n.Error(nil))
+	assert.Equal(t, "Access denied", ctxi18n.NewError("errors.denied", nil).Error())
+	assert.Equal(t, "Access denied: boom", ctxi18n.WrapError(errors.New("boom"), "errors.denied", nil).Error())
+
+	ctx, cerr := ctxi18n.WithLocale(context.Background(), "inv")
+	require.NoError(t, cerr)
+	assert.Equal(t, "Access denied", err.Localized(ctx).Message)
+}
@@ -0,0 +1,117 @@
+// Package grpci18n provides gRPC interceptors to determine the locale of
+// incoming calls from their metadata, and to propagate the locale of the
+// context to outgoing calls.
+package grpci18n
+
+import (
+	"context"
+	"strings"
+
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/i18n"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// MetadataKey is the metadata key used to send the requested locales in
+// the "Accept-Language" header format.
+const MetadataKey = "accept-language"
+
+// Option is used to configure the server interceptors.
+type Option func(*interceptor)
+
+// WithBundle sets the bundle to find locales in, instead of the default
+// bundle used by the `ctxi18n` package functions.
+func WithBundle(b *ctxi18n.Bundle) Option {
+	return func(i *interceptor) {
+		i.bundle = b
+	}
+}
+
+type interceptor struct {
+	bundle *ctxi18n.Bundle
+}
+
+func newInterceptor(opts []Option) *interceptor {
+	i := &interceptor{
+		bundle: ctxi18n.Default(),
+	}
+	for _, opt := range opts {
+		opt(i)
+	}
+	return i
+}
+
+// UnaryServerInterceptor adds the locale matching the incoming metadata to
+// the context of unary calls, or the bundle's fallbacks and default locale
+// if there is no match. Calls will proceed without a locale if none could
+// be found.
+func UnaryServerInterceptor(opts ...Option) grpc.UnaryServerInterceptor {
+	i := newInterceptor(opts)
+	return func(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(i.withLocale(ctx), req)
+	}
+}
+
+// StreamServerInterceptor adds the locale matching the incoming metadata
+// to the context of streaming calls, in the same way as the unary
+// interceptor.
+func StreamServerInterceptor(opts ...Option) grpc.StreamServerInterceptor {
+	i := newInterceptor(opts)
+	return func(srv any, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{
+			ServerStream: ss,
+			ctx:          i.withLocale(ss.Context()),
+		})
+	}
+}
+
+func (i *interceptor) withLocale(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	locale := strings.Join(md.Get(MetadataKey), ",")
+	lctx, err := i.bundle.WithLocale(ctx, locale)
+	if err != nil {
+		return ctx
+	}
+	return lctx
+}
+
+// serverStream replaces the context of the wrapped stream.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context provides the context containing the locale.
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// UnaryClientInterceptor adds the code of the locale in the context, if
+// any, to the outgoing metadata of unary calls.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(outgoing(ctx), method, req, reply, cc, opts...)
+	}
+}
+
+// StreamClientInterceptor adds the code of the locale in the context, if
+// any, to the outgoing metadata of streaming calls.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(outgoing(ctx), desc, cc, method, opts...)
+	}
+}
+
+// outgoing adds the locale to the metadata, unless a value has already
+// been set explicitly.
+func outgoing(ctx context.Context) context.Context {
+	l := i18n.GetLocale(ctx)
+	if l == nil {
+		return ctx
+	}
+	if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get(MetadataKey)) > 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, MetadataKey, l.Code().String())
+}
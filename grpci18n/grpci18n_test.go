@@ -0,0 +1,126 @@
+package grpci18n_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/grpci18n"
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// healthServer responds to every call with an error translated into the
+// locale found in the context.
+type healthServer struct {
+	healthpb.UnimplementedHealthServer
+}
+
+func (healthServer) Check(ctx context.Context, _ *healthpb.HealthCheckRequest) (*healthpb.HealthCheckResponse, error) {
+	return nil, localizedError(ctx)
+}
+
+func (healthServer) Watch(_ *healthpb.HealthCheckRequest, ss healthpb.Health_WatchServer) error {
+	return localizedError(ss.Context())
+}
+
+func localizedError(ctx context.Context) error {
+	l := i18n.GetLocale(ctx)
+	if l == nil {
+		return status.Error(codes.NotFound, "none")
+	}
+	return status.Error(codes.NotFound, l.Code().String()+": "+i18n.T(ctx, "login.button"))
+}
+
+func newClient(t *testing.T, sopts []grpci18n.Option, copts ...grpc.DialOption) healthpb.HealthClient {
+	t.Helper()
+	b := ctxi18n.NewBundle()
+	require.NoError(t, b.Load(examples.Content))
+	sopts = append([]grpci18n.Option{grpci18n.WithBundle(b)}, sopts...)
+
+	lis := bufconn.Listen(1024 * 1024)
+	srv := grpc.NewServer(
+		grpc.UnaryInterceptor(grpci18n.UnaryServerInterceptor(sopts...)),
+		grpc.StreamInterceptor(grpci18n.StreamServerInterceptor(sopts...)),
+	)
+	healthpb.RegisterHealthServer(srv, healthServer{})
+	go func() {
+		_ = srv.Serve(lis)
+	}()
+	t.Cleanup(srv.Stop)
+
+	copts = append(copts,
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	conn, err := grpc.Dial("passthrough:///bufnet", copts...)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+	return healthpb.NewHealthClient(conn)
+}
+
+func check(ctx context.Context, c healthpb.HealthClient) string {
+	_, err := c.Check(ctx, &healthpb.HealthCheckRequest{})
+	return status.Convert(err).Message()
+}
+
+func watch(ctx context.Context, c healthpb.HealthClient) string {
+	s, err := c.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err == nil {
+		_, err = s.Recv()
+	}
+	return status.Convert(err).Message()
+}
+
+func TestServerInterceptors(t *testing.T) {
+	c := newClient(t, nil)
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), grpci18n.MetadataKey, "fr,es;q=0.9")
+	assert.Equal(t, "es: Iniciar Sesión", check(ctx, c))
+	assert.Equal(t, "es: Iniciar Sesión", watch(ctx, c))
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), grpci18n.MetadataKey, "fr", grpci18n.MetadataKey, "es")
+	assert.Equal(t, "es: Iniciar Sesión", check(ctx, c), "multiple values")
+
+	ctx = context.Background()
+	assert.Equal(t, "en: Log In", check(ctx, c), "default locale")
+	assert.Equal(t, "en: Log In", watch(ctx, c))
+}
+
+func TestServerInterceptorsMissingLocale(t *testing.T) {
+	b := ctxi18n.NewBundle(ctxi18n.WithDefaultLocale("fr"))
+	c := newClient(t, []grpci18n.Option{grpci18n.WithBundle(b)})
+	assert.Equal(t, "none", check(context.Background(), c))
+	assert.Equal(t, "none", watch(context.Background(), c))
+}
+
+func TestClientInterceptors(t *testing.T) {
+	c := newClient(t, nil,
+		grpc.WithUnaryInterceptor(grpci18n.UnaryClientInterceptor()),
+		grpc.WithStreamInterceptor(grpci18n.StreamClientInterceptor()),
+	)
+
+	es := i18n.NewLocale("es", nil)
+	ctx := es.WithContext(context.Background())
+	assert.Equal(t, "es: Iniciar Sesión", check(ctx, c))
+	assert.Equal(t, "es: Iniciar Sesión", watch(ctx, c))
+
+	ctx = metadata.AppendToOutgoingContext(ctx, grpci18n.MetadataKey, "en")
+	assert.Equal(t, "en: Log In", check(ctx, c), "explicit metadata kept")
+
+	assert.Equal(t, "en: Log In", check(context.Background(), c), "no locale")
+}
@@ -0,0 +1,261 @@
+package httpi18n
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+// Catalog query parameters.
+const (
+	// CatalogLocaleParam is the query parameter used to request a locale.
+	CatalogLocaleParam = "locale"
+	// CatalogKeysParam is the query parameter used to request a comma
+	// separated list of key prefixes.
+	CatalogKeysParam = "keys"
+)
+
+// CatalogOption is used to configure the catalog handler.
+type CatalogOption func(*catalog)
+
+// CatalogBundle sets the bundle to find locales in, instead of the default
+// bundle used by the `ctxi18n` package functions.
+func CatalogBundle(b *ctxi18n.Bundle) CatalogOption {
+	return func(c *catalog) {
+		c.bundle = b
+	}
+}
+
+// CatalogKeys restricts the entries served to those found under the
+// provided keys, like `login` or `date.formats`.
+func CatalogKeys(keys ...string) CatalogOption {
+	return func(c *catalog) {
+		c.keys = keys
+	}
+}
+
+// CatalogFlat serves the entries as a flat object with the complete path of
+// each key, like `login.button`, instead of nested objects.
+func CatalogFlat() CatalogOption {
+	return func(c *catalog) {
+		c.flat = true
+	}
+}
+
+// maxCatalogEntries limits the number of prepared responses kept by each
+// handler, as clients may request any combination of keys.
+const maxCatalogEntries = 256
+
+type catalog struct {
+	bundle *ctxi18n.Bundle
+	keys   []string
+	flat   bool
+
+	mu    sync.Mutex
+	cache map[catalogKey]*catalogEntry
+}
+
+type catalogKey struct {
+	code i18n.Code
+	keys string
+}
+
+// catalogEntry contains the response prepared from a locale's dictionary,
+// which will be prepared again if the dictionary is replaced.
+type catalogEntry struct {
+	dict *i18n.Dict
+	etag string
+	body []byte
+	gzip []byte
+}
+
+// Catalog provides a handler that serves the entries of a locale as JSON,
+// so that frontend applications may use the same translations. The locale
+// is taken from the `locale` query parameter, or from the request's context
+// if not provided, for example when using the Middleware, which will also
+// set the "Vary" header for the sources it uses. Clients may filter the
+// entries further with a comma separated list of keys in the `keys` query
+// parameter.
+//
+// Responses include a strong ETag based on their contents so that clients
+// can avoid downloading the same entries again, and are compressed with
+// gzip when supported. Responses are cached until the locale's entries
+// change.
+func Catalog(opts ...CatalogOption) http.Handler {
+	c := &catalog{
+		bundle: ctxi18n.Default(),
+		cache:  make(map[catalogKey]*catalogEntry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// ServeHTTP prepares the response.
+func (c *catalog) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+
+	l := c.locale(r)
+	if l == nil {
+		http.Error(w, i18n.ErrMissingLocale.Error(), http.StatusNotFound)
+		return
+	}
+
+	e, err := c.entry(l, parseKeys(r.URL.Query().Get(CatalogKeysParam)))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	body := e.body
+	etag := e.etag
+	gz := acceptsGzip(r)
+	if gz {
+		body = e.gzip
+		etag += "-gzip"
+	}
+	etag = `"` + etag + `"`
+
+	h := w.Header()
+	h.Set("Content-Type", "application/json; charset=utf-8")
+	h.Set("Content-Language", l.Code().String())
+	h.Set("ETag", etag)
+	h.Set("Cache-Control", "no-cache")
+	h.Add("Vary", "Accept-Encoding")
+
+	if matchETag(r.Header.Get("If-None-Match"), etag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if gz {
+		h.Set("Content-Encoding", "gzip")
+	}
+	if r.Method == http.MethodHead {
+		return
+	}
+	_, _ = w.Write(body)
+}
+
+// entry provides the cached response for the locale and keys, preparing
+// it again if the locale's dictionary has changed.
+func (c *catalog) entry(l *i18n.Locale, keys []string) (*catalogEntry, error) {
+	d := l.Dict()
+	k := catalogKey{code: l.Code(), keys: strings.Join(keys, ",")}
+
+	c.mu.Lock()
+	e, ok := c.cache[k]
+	c.mu.Unlock()
+	if ok && e.dict == d {
+		return e, nil
+	}
+
+	e, err := c.prepare(d, keys)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.cache) >= maxCatalogEntries {
+		c.cache = make(map[catalogKey]*catalogEntry)
+	}
+	c.cache[k] = e
+	return e, nil
+}
+
+// prepare filters and encodes the dictionary.
+func (c *catalog) prepare(d *i18n.Dict, keys []string) (*catalogEntry, error) {
+	fd := d.Filter(c.keys...)
+	if len(keys) > 0 {
+		fd = fd.Filter(keys...)
+	}
+	var body []byte
+	var err error
+	if c.flat {
+		body, err = fd.MarshalFlatJSON()
+	} else {
+		body, err = json.Marshal(fd)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	buf := new(bytes.Buffer)
+	zw := gzip.NewWriter(buf)
+	if _, err := zw.Write(body); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+
+	sum := sha256.Sum256(body)
+	return &catalogEntry{
+		dict: d,
+		etag: hex.EncodeToString(sum[:16]),
+		body: body,
+		gzip: buf.Bytes(),
+	}, nil
+}
+
+func (c *catalog) locale(r *http.Request) *i18n.Locale {
+	if code := r.URL.Query().Get(CatalogLocaleParam); code != "" {
+		return c.bundle.Get(i18n.Code(code))
+	}
+	return i18n.GetLocale(r.Context())
+}
+
+// parseKeys provides the sorted list of distinct keys from a comma
+// separated list.
+func parseKeys(q string) []string {
+	var keys []string
+	for _, k := range strings.Split(q, ",") {
+		if k = strings.TrimSpace(k); k != "" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return slices.Compact(keys)
+}
+
+// acceptsGzip checks if the client supports gzip responses.
+func acceptsGzip(r *http.Request) bool {
+	for _, enc := range strings.Split(r.Header.Get("Accept-Encoding"), ",") {
+		name, q, _ := strings.Cut(strings.TrimSpace(enc), ";")
+		if strings.TrimSpace(name) != "gzip" {
+			continue
+		}
+		q = strings.ReplaceAll(q, " ", "")
+		if v, ok := strings.CutPrefix(q, "q="); ok {
+			f, err := strconv.ParseFloat(v, 64)
+			return err == nil && f > 0
+		}
+		return true
+	}
+	return false
+}
+
+// matchETag checks if the If-None-Match header contains the ETag.
+func matchETag(header, etag string) bool {
+	for _, v := range strings.Split(header, ",") {
+		v = strings.TrimPrefix(strings.TrimSpace(v), "W/")
+		if v == etag || v == "*" {
+			return true
+		}
+	}
+	return false
+}
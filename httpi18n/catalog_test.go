@@ -0,0 +1,156 @@
+package httpi18n_test
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+
+	"github.com/invopop/ctxi18n/httpi18n"
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCatalog(t *testing.T) {
+	b := newBundle(t)
+	h := httpi18n.Catalog(httpi18n.CatalogBundle(b))
+
+	r := httptest.NewRequest(http.MethodGet, "/?locale=es&keys=login", nil)
+	w := serve(h, r)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	assert.Contains(t, w.Body.String(), `"login":{`)
+	assert.Contains(t, w.Body.String(), `"button":"Iniciar Sesión"`)
+	assert.NotContains(t, w.Body.String(), "about_us")
+
+	etag := w.Header().Get("ETag")
+	assert.Regexp(t, `^"[0-9a-f]{32}"$`, etag)
+
+	t.Run("not modified", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?locale=es&keys=login", nil)
+		r.Header.Set("If-None-Match", `"other", `+etag)
+		w := serve(h, r)
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+		assert.Equal(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("different content", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?locale=en&keys=login", nil)
+		r.Header.Set("If-None-Match", etag)
+		w := serve(h, r)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.NotEqual(t, etag, w.Header().Get("ETag"))
+	})
+
+	t.Run("flat", func(t *testing.T) {
+		h := httpi18n.Catalog(
+			httpi18n.CatalogBundle(b),
+			httpi18n.CatalogKeys("login", "date"),
+			httpi18n.CatalogFlat(),
+		)
+		r := httptest.NewRequest(http.MethodGet, "/?locale=en", nil)
+		w := serve(h, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		out := make(map[string]any)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+		assert.Equal(t, "Log In", out["login.button"])
+		assert.Len(t, out["date.day_names"], 7)
+		assert.NotContains(t, out, "about_us")
+
+		r = httptest.NewRequest(http.MethodGet, "/?locale=en&keys=date", nil)
+		w = serve(h, r)
+		out = make(map[string]any)
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &out))
+		assert.Len(t, out, 1, "client keys within server keys")
+	})
+
+	t.Run("gzip", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?locale=es&keys=login", nil)
+		r.Header.Set("Accept-Encoding", "br, gzip;q=0.8")
+		w := serve(h, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+		assert.Equal(t, etag[:len(etag)-1]+`-gzip"`, w.Header().Get("ETag"))
+		assert.Contains(t, w.Header().Values("Vary"), "Accept-Encoding")
+
+		zr, err := gzip.NewReader(w.Body)
+		require.NoError(t, err)
+		data, err := io.ReadAll(zr)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), `"button":"Iniciar Sesión"`)
+
+		r = httptest.NewRequest(http.MethodGet, "/?locale=es", nil)
+		r.Header.Set("Accept-Encoding", "gzip;q=0")
+		w = serve(h, r)
+		assert.Empty(t, w.Header().Get("Content-Encoding"))
+	})
+
+	t.Run("context locale", func(t *testing.T) {
+		h := httpi18n.Middleware(httpi18n.WithBundle(b))(h)
+		r := httptest.NewRequest(http.MethodGet, "/?keys=login.button", nil)
+		r.Header.Set("Accept-Language", "es")
+		w := serve(h, r)
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.JSONEq(t, `{"login":{"button":"Iniciar Sesión"}}`, w.Body.String())
+		assert.Equal(t, []string{"Accept-Language", "Accept-Encoding"}, w.Header().Values("Vary"))
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		b := newBundle(t)
+		h := httpi18n.Catalog(httpi18n.CatalogBundle(b))
+		get := func(q string) *httptest.ResponseRecorder {
+			return serve(h, httptest.NewRequest(http.MethodGet, "/?locale=en&"+q, nil))
+		}
+
+		w := get("keys=login,date")
+		etag := w.Header().Get("ETag")
+		assert.Equal(t, etag, get("keys=date,%20login,login").Header().Get("ETag"), "keys normalized")
+		assert.Equal(t, w.Body.String(), get("keys=login,date").Body.String())
+
+		require.NoError(t, b.Locales().Load(fstest.MapFS{
+			"en.yaml": {Data: []byte("en:\n  login:\n    button: \"Enter\"\n")},
+		}, i18n.Override()))
+		w = get("keys=login,date")
+		assert.NotEqual(t, etag, w.Header().Get("ETag"), "updated after reload")
+		assert.Contains(t, w.Body.String(), `"button":"Enter"`)
+	})
+
+	t.Run("overrides", func(t *testing.T) {
+		b := newBundle(t)
+		od := i18n.NewDict()
+		od.Add("login", i18n.M{"button": "Sign In"})
+		o := i18n.NewOverrides(od)
+		h := httpi18n.Middleware(httpi18n.WithBundle(b))(httpi18n.Catalog(httpi18n.CatalogBundle(b)))
+		get := func() *httptest.ResponseRecorder {
+			r := httptest.NewRequest(http.MethodGet, "/?keys=login.button", nil)
+			r = r.WithContext(i18n.WithOverrides(r.Context(), o))
+			return serve(h, r)
+		}
+
+		w := get()
+		assert.JSONEq(t, `{"login":{"button":"Sign In"}}`, w.Body.String())
+		assert.Equal(t, w.Header().Get("ETag"), get().Header().Get("ETag"))
+	})
+
+	t.Run("missing locale", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodGet, "/?locale=fr", nil)
+		w := serve(h, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		r = httptest.NewRequest(http.MethodGet, "/", nil)
+		w = serve(h, r)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("method", func(t *testing.T) {
+		r := httptest.NewRequest(http.MethodPost, "/?locale=en", nil)
+		w := serve(h, r)
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	})
+}
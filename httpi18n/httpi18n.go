@@ -0,0 +1,199 @@
+// Package httpi18n provides middleware to determine the locale of each
+// HTTP request and make it available in the request's context.
+package httpi18n
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/invopop/ctxi18n"
+)
+
+// DefaultCookieMaxAge is how long persisted locale choices are kept.
+const DefaultCookieMaxAge = 365 * 24 * time.Hour
+
+// Source determines which locales were requested by the client.
+type Source struct {
+	// Lookup provides the requested locales in the "Accept-Language"
+	// header format, or an empty string if not available.
+	Lookup func(r *http.Request) string
+	// Explicit sources contain a deliberate choice made by the user,
+	// which may be persisted in a cookie.
+	Explicit bool
+	// Vary is the request header the source depends on, if any.
+	Vary string
+}
+
+// PathPrefix uses the first segment of the URL path, like `es` in
+// `/es/products`, as an explicit choice. Paths that don't start with a
+// defined locale are ignored.
+func PathPrefix() Source {
+	return Source{
+		Lookup: func(r *http.Request) string {
+			p := strings.TrimPrefix(r.URL.Path, "/")
+			seg, _, _ := strings.Cut(p, "/")
+			return seg
+		},
+		Explicit: true,
+	}
+}
+
+// Query uses the URL query parameter with the provided name as an
+// explicit choice.
+func Query(name string) Source {
+	return Source{
+		Lookup: func(r *http.Request) string {
+			return r.URL.Query().Get(name)
+		},
+		Explicit: true,
+	}
+}
+
+// Cookie uses the value of the cookie with the provided name.
+func Cookie(name string) Source {
+	return Source{
+		Lookup: func(r *http.Request) string {
+			c, err := r.Cookie(name)
+			if err != nil {
+				return ""
+			}
+			return c.Value
+		},
+		Vary: "Cookie",
+	}
+}
+
+// Header uses the "Accept-Language" header sent by the client.
+func Header() Source {
+	return Source{
+		Lookup: func(r *http.Request) string {
+			return r.Header.Get("Accept-Language")
+		},
+		Vary: "Accept-Language",
+	}
+}
+
+// Func uses the provided function to determine the locales, for example
+// from the preferences in the current user's profile.
+func Func(fn func(r *http.Request) string) Source {
+	return Source{Lookup: fn}
+}
+
+// Option is used to configure the middleware.
+type Option func(*middleware)
+
+// WithBundle sets the bundle to find locales in, instead of the default
+// bundle used by the `ctxi18n` package functions.
+func WithBundle(b *ctxi18n.Bundle) Option {
+	return func(m *middleware) {
+		m.bundle = b
+	}
+}
+
+// WithSources defines the ordered list of sources used to determine the
+// locale, replacing the default of using only the "Accept-Language"
+// header. The first source that provides a defined locale wins, and the
+// bundle's fallbacks and default locale are used if none do.
+func WithSources(sources ...Source) Option {
+	return func(m *middleware) {
+		m.sources = sources
+	}
+}
+
+// PersistCookie will store locales chosen explicitly in a cookie with the
+// provided name, so that they may be used in future requests by including
+// a Cookie source with the same name.
+func PersistCookie(name string) Option {
+	return func(m *middleware) {
+		m.cookie = name
+	}
+}
+
+type middleware struct {
+	bundle  *ctxi18n.Bundle
+	sources []Source
+	cookie  string
+}
+
+// Middleware prepares a handler wrapper that will add the locale to each
+// request's context and set the "Content-Language" and "Vary" response
+// headers. Requests for which no locale could be found, including the
+// default, will be passed on without one.
+func Middleware(opts ...Option) func(http.Handler) http.Handler {
+	m := &middleware{
+		bundle:  ctxi18n.Default(),
+		sources: []Source{Header()},
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m.wrap
+}
+
+func (m *middleware) wrap(next http.Handler) http.Handler {
+	vary := make([]string, 0, len(m.sources))
+	for _, s := range m.sources {
+		if s.Vary != "" && !contains(vary, s.Vary) {
+			vary = append(vary, s.Vary)
+		}
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, v := range vary {
+			w.Header().Add("Vary", v)
+		}
+
+		locale, explicit := m.lookup(r)
+		ctx, err := m.bundle.WithLocale(r.Context(), locale)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		l := ctxi18n.Locale(ctx)
+		w.Header().Set("Content-Language", l.Code().String())
+		if explicit && m.cookie != "" {
+			m.persist(w, r, l.Code().String())
+		}
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// lookup finds the first source that provides a defined locale.
+func (m *middleware) lookup(r *http.Request) (string, bool) {
+	for _, s := range m.sources {
+		v := s.Lookup(r)
+		if v == "" {
+			continue
+		}
+		if m.bundle.Match(v) != nil {
+			return v, s.Explicit
+		}
+	}
+	return "", false
+}
+
+func (m *middleware) persist(w http.ResponseWriter, r *http.Request, code string) {
+	if c, err := r.Cookie(m.cookie); err == nil && c.Value == code {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     m.cookie,
+		Value:    code,
+		Path:     "/",
+		MaxAge:   int(DefaultCookieMaxAge / time.Second),
+		Secure:   r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package httpi18n_test
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/invopop/ctxi18n"
+	"github.com/invopop/ctxi18n/httpi18n"
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newBundle(t *testing.T, opts ...ctxi18n.Option) *ctxi18n.Bundle {
+	t.Helper()
+	b := ctxi18n.NewBundle(opts...)
+	require.NoError(t, b.Load(examples.Content))
+	return b
+}
+
+var echo = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+	l := i18n.GetLocale(r.Context())
+	if l == nil {
+		_, _ = w.Write([]byte("none"))
+		return
+	}
+	_, _ = w.Write([]byte(l.Code().String() + ": " + i18n.T(r.Context(), "login.button")))
+})
+
+func serve(h http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	h := httpi18n.Middleware(httpi18n.WithBundle(newBundle(t)))(echo)
+
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "fr-FR,es;q=0.9,en;q=0.8")
+	w := serve(h, r)
+	assert.Equal(t, "es: Iniciar Sesión", w.Body.String())
+	assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	assert.Equal(t, []string{"Accept-Language"}, w.Header().Values("Vary"))
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	w = serve(h, r)
+	assert.Equal(t, "en: Log In", w.Body.String(), "default locale")
+	assert.Equal(t, "en", w.Header().Get("Content-Language"))
+}
+
+func TestMiddlewareSources(t *testing.T) {
+	profile := httpi18n.Func(func(r *http.Request) string {
+		return r.Header.Get("X-User-Locale")
+	})
+	h := httpi18n.Middleware(
+		httpi18n.WithBundle(newBundle(t)),
+		httpi18n.WithSources(
+			httpi18n.PathPrefix(),
+			httpi18n.Query("locale"),
+			httpi18n.Cookie("locale"),
+			profile,
+			httpi18n.Header(),
+		),
+	)(echo)
+
+	tests := []struct {
+		name   string
+		target string
+		cookie string
+		user   string
+		header string
+		want   string
+	}{
+		{"path", "/es/products?locale=en", "en", "en", "en", "es"},
+		{"unknown path", "/products?locale=es", "en", "en", "en", "es"},
+		{"query", "/?locale=es", "en", "", "", "es"},
+		{"bad query", "/?locale=fr", "es", "en", "en", "es"},
+		{"cookie", "/", "es", "en", "en", "es"},
+		{"profile", "/", "", "es", "en", "es"},
+		{"header", "/", "", "", "es", "es"},
+		{"default", "/", "", "", "fr", "en"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: "locale", Value: tt.cookie})
+			}
+			r.Header.Set("X-User-Locale", tt.user)
+			r.Header.Set("Accept-Language", tt.header)
+			w := serve(h, r)
+			assert.Equal(t, tt.want, w.Header().Get("Content-Language"))
+			assert.Equal(t, []string{"Cookie", "Accept-Language"}, w.Header().Values("Vary"))
+			assert.Empty(t, w.Result().Cookies(), "no cookie without persist")
+		})
+	}
+}
+
+func TestMiddlewarePersistCookie(t *testing.T) {
+	h := httpi18n.Middleware(
+		httpi18n.WithBundle(newBundle(t)),
+		httpi18n.WithSources(httpi18n.Query("locale"), httpi18n.Cookie("lang"), httpi18n.Header()),
+		httpi18n.PersistCookie("lang"),
+	)(echo)
+
+	r := httptest.NewRequest(http.MethodGet, "/?locale=es", nil)
+	r.TLS = &tls.ConnectionState{}
+	w := serve(h, r)
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	assert.Equal(t, "lang", cookies[0].Name)
+	assert.Equal(t, "es", cookies[0].Value)
+	assert.True(t, cookies[0].Secure)
+	assert.True(t, cookies[0].HttpOnly)
+	assert.Equal(t, "/", cookies[0].Path)
+	assert.Positive(t, cookies[0].MaxAge)
+
+	r = httptest.NewRequest(http.MethodGet, "/?locale=es", nil)
+	r.AddCookie(&http.Cookie{Name: "lang", Value: "es"})
+	w = serve(h, r)
+	assert.Empty(t, w.Result().Cookies(), "unchanged")
+
+	r = httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept-Language", "es")
+	w = serve(h, r)
+	assert.Equal(t, "es", w.Header().Get("Content-Language"))
+	assert.Empty(t, w.Result().Cookies(), "not explicit")
+}
+
+func TestMiddlewareMissingLocale(t *testing.T) {
+	b := newBundle(t, ctxi18n.WithDefaultLocale("fr"))
+	h := httpi18n.Middleware(httpi18n.WithBundle(b))(echo)
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := serve(h, r)
+	assert.Equal(t, "none", w.Body.String())
+	assert.Empty(t, w.Header().Get("Content-Language"))
+}
@@ -21,6 +21,119 @@ func (c Code) Base() Code {
 	return Code(out[0])
 }
 
+// Text directions provided by Direction.
+const (
+	DirectionLTR = "ltr"
+	DirectionRTL = "rtl"
+)
+
+var (
+	rtlLanguages = map[Code]bool{
+		"ar": true, "arc": true, "ckb": true, "dv": true, "fa": true, "he": true,
+		"ks": true, "ku": true, "ps": true, "sd": true, "syr": true, "ug": true,
+		"ur": true, "yi": true,
+	}
+	rtlScripts = map[string]bool{
+		"adlm": true, "arab": true, "hebr": true, "nkoo": true, "rohg": true,
+		"syrc": true, "thaa": true,
+	}
+	ltrScripts = map[string]bool{
+		"cyrl": true, "latn": true,
+	}
+)
+
+// Direction determines if texts in the language are written from left to
+// right or right to left, using the script sub-tag if present, and
+// returns either "ltr" or "rtl" for use in the HTML `dir` attribute.
+func (c Code) Direction() string {
+	for _, p := range strings.Split(c.String(), "-")[1:] {
+		p = strings.ToLower(p)
+		switch {
+		case rtlScripts[p]:
+			return DirectionRTL
+		case ltrScripts[p]:
+			return DirectionLTR
+		}
+	}
+	if rtlLanguages[Code(strings.ToLower(c.Base().String()))] {
+		return DirectionRTL
+	}
+	return DirectionLTR
+}
+
+// isCode performs a simple check to see if the provided string looks like
+// a language code with an optional set of sub-tags, like `es` or `es-419`.
+func isCode(s string) bool {
+	parts := strings.Split(s, "-")
+	if l := len(parts[0]); l < 2 || l > 3 {
+		return false
+	}
+	for i, p := range parts {
+		if p == "" || len(p) > 8 {
+			return false
+		}
+		for _, r := range p {
+			switch {
+			case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+				// good
+			case r >= '0' && r <= '9' && i > 0:
+				// good
+			default:
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// languages contains the ISO 639 language codes used by the CLDR that are
+// recognized when inferring the locale from a file's path.
+var languages = makeSet(
+	// ISO 639-1
+	"aa", "ab", "ae", "af", "ak", "am", "an", "ar", "as", "av", "ay", "az",
+	"ba", "be", "bg", "bh", "bi", "bm", "bn", "bo", "br", "bs", "ca", "ce",
+	"ch", "co", "cr", "cs", "cu", "cv", "cy", "da", "de", "dv", "dz", "ee",
+	"el", "en", "eo", "es", "et", "eu", "fa", "ff", "fi", "fj", "fo", "fr",
+	"fy", "ga", "gd", "gl", "gn", "gu", "gv", "ha", "he", "hi", "ho", "hr",
+	"ht", "hu", "hy", "hz", "ia", "id", "ie", "ig", "ii", "ik", "io", "is",
+	"it", "iu", "ja", "jv", "ka", "kg", "ki", "kj", "kk", "kl", "km", "kn",
+	"ko", "kr", "ks", "ku", "kv", "kw", "ky", "la", "lb", "lg", "li", "ln",
+	"lo", "lt", "lu", "lv", "mg", "mh", "mi", "mk", "ml", "mn", "mr", "ms",
+	"mt", "my", "na", "nb", "nd", "ne", "ng", "nl", "nn", "no", "nr", "nv",
+	"ny", "oc", "oj", "om", "or", "os", "pa", "pi", "pl", "ps", "pt", "qu",
+	"rm", "rn", "ro", "ru", "rw", "sa", "sc", "sd", "se", "sg", "si", "sk",
+	"sl", "sm", "sn", "so", "sq", "sr", "ss", "st", "su", "sv", "sw", "ta",
+	"te", "tg", "th", "ti", "tk", "tl", "tn", "to", "tr", "ts", "tt", "tw",
+	"ty", "ug", "uk", "ur", "uz", "ve", "vi", "vo", "wa", "wo", "xh", "yi",
+	"yo", "za", "zh", "zu",
+	// ISO 639-2 and 639-3, without an ISO 639-1 equivalent
+	"agq", "arc", "asa", "ast", "bas", "bem", "bez", "bgc", "bho", "brx",
+	"ccp", "ceb", "cgg", "chr", "ckb", "dav", "dje", "doi", "dsb", "dua",
+	"dyo", "ebu", "ewo", "fil", "fur", "gsw", "guz", "hak", "haw", "hsb",
+	"jgo", "jmc", "kab", "kam", "kde", "kea", "kgp", "khq", "kkj", "kln",
+	"kok", "ksb", "ksf", "ksh", "lag", "lkt", "lrc", "luo", "luy", "mai",
+	"mas", "mer", "mfe", "mgh", "mgo", "mni", "mua", "mzn", "nan", "naq",
+	"nds", "nmg", "nnh", "nqo", "nso", "nus", "nyn", "pcm", "raj", "rof",
+	"rwk", "sah", "saq", "sat", "sbp", "seh", "ses", "shi", "smn", "syr",
+	"teo", "tok", "twq", "tzm", "vai", "vun", "wae", "wuu", "xog", "yav",
+	"yrl", "yue", "zgh",
+)
+
+func makeSet(items ...string) map[string]bool {
+	m := make(map[string]bool, len(items))
+	for _, i := range items {
+		m[i] = true
+	}
+	return m
+}
+
+// isLanguageCode checks that the string looks like a code and that its
+// base is a known language, so that words like `web` or `faq` found in
+// file paths are not mistaken for locales.
+func isLanguageCode(s string) bool {
+	return isCode(s) && languages[strings.ToLower(Code(s).Base().String())]
+}
+
 // ParseAcceptLanguage provides an ordered set of codes extracted
 // from an HTTP "Accept-Language" header as defined in RFC9110.
 // Current implementation will ignore quality values and instead
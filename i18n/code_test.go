@@ -36,6 +36,18 @@ func TestCodeBase(t *testing.T) {
 	assert.Equal(t, "", c.Base().String())
 }
 
+func TestCodeDirection(t *testing.T) {
+	assert.Equal(t, "ltr", i18n.Code("en").Direction())
+	assert.Equal(t, "ltr", i18n.Code("es-419").Direction())
+	assert.Equal(t, "ltr", i18n.Code("").Direction())
+	assert.Equal(t, "rtl", i18n.Code("ar").Direction())
+	assert.Equal(t, "rtl", i18n.Code("he-IL").Direction())
+	assert.Equal(t, "rtl", i18n.Code("ar-XB").Direction())
+	assert.Equal(t, "rtl", i18n.Code("az-Arab").Direction())
+	assert.Equal(t, "rtl", i18n.Code("man-Nkoo-GN").Direction())
+	assert.Equal(t, "ltr", i18n.Code("ku-Latn").Direction())
+}
+
 func TestParseAcceptLanguage(t *testing.T) {
 	list := i18n.ParseAcceptLanguage("en")
 	assert.Equal(t, []i18n.Code{"en"}, list)
@@ -0,0 +1,177 @@
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// modulePath is used to skip the frames from this module when determining
+// where a missing key was requested from.
+const modulePath = "github.com/invopop/ctxi18n"
+
+// MissingKey describes a key that was requested but not found.
+type MissingKey struct {
+	// Locale code the key was requested for, empty if there was no locale
+	// in the context.
+	Locale Code `json:"locale"`
+	// Key that was requested.
+	Key string `json:"key"`
+	// Hits is the number of times the key was requested.
+	Hits int `json:"hits"`
+	// Caller is the file and line where the key was first requested.
+	Caller string `json:"caller,omitempty"`
+}
+
+// Collector records every missing key requested through the context based
+// translation methods, so that a report can be prepared after running a
+// test suite or a staging environment. Use the `Handle` method as the
+// missing key handler.
+type Collector struct {
+	mu      sync.Mutex
+	entries map[collectorKey]*MissingKey
+}
+
+type collectorKey struct {
+	code Code
+	key  string
+}
+
+// NewCollector prepares a new empty collector.
+func NewCollector() *Collector {
+	return &Collector{
+		entries: make(map[collectorKey]*MissingKey),
+	}
+}
+
+// Handle records the missing key. It implements the MissingKeyHandler
+// signature and never provides a replacement text.
+func (c *Collector) Handle(_ context.Context, code Code, key string, _ ...any) (string, bool) {
+	ck := collectorKey{code, key}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if e, ok := c.entries[ck]; ok {
+		e.Hits++
+		return "", false
+	}
+	c.entries[ck] = &MissingKey{
+		Locale: code,
+		Key:    key,
+		Hits:   1,
+		Caller: caller(),
+	}
+	return "", false
+}
+
+// Missing provides the list of missing keys ordered by locale and key.
+func (c *Collector) Missing() []MissingKey {
+	c.mu.Lock()
+	list := make([]MissingKey, 0, len(c.entries))
+	for _, e := range c.entries {
+		list = append(list, *e)
+	}
+	c.mu.Unlock()
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Locale != list[j].Locale {
+			return list[i].Locale < list[j].Locale
+		}
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// Reset removes all the keys recorded so far.
+func (c *Collector) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries = make(map[collectorKey]*MissingKey)
+}
+
+// WriteJSON writes the list of missing keys as a JSON array.
+func (c *Collector) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(c.Missing())
+}
+
+// WriteYAML writes a skeleton locale file containing every missing key
+// with an empty text, ready to be filled in and merged into the catalog.
+// Keys requested without a locale are ignored.
+func (c *Collector) WriteYAML(w io.Writer) error {
+	out := make(map[string]any)
+	for _, e := range c.Missing() {
+		if e.Locale == "" {
+			continue
+		}
+		m, ok := out[e.Locale.String()].(map[string]any)
+		if !ok {
+			m = make(map[string]any)
+			out[e.Locale.String()] = m
+		}
+		addSkeletonKey(m, strings.Split(e.Key, "."))
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(out); err != nil {
+		return fmt.Errorf("encoding skeleton: %w", err)
+	}
+	return enc.Close()
+}
+
+// addSkeletonKey adds an empty text for the key path, giving preference to
+// maps when a key is both a text and the prefix of another key.
+func addSkeletonKey(m map[string]any, path []string) {
+	if len(path) == 1 {
+		if _, ok := m[path[0]]; !ok {
+			m[path[0]] = ""
+		}
+		return
+	}
+	sub, ok := m[path[0]].(map[string]any)
+	if !ok {
+		sub = make(map[string]any)
+		m[path[0]] = sub
+	}
+	addSkeletonKey(sub, path[1:])
+}
+
+// caller determines the first location outside of this module in the
+// current call stack.
+func caller() string {
+	pcs := make([]uintptr, 32)
+	n := runtime.Callers(3, pcs)
+	frames := runtime.CallersFrames(pcs[:n])
+	for {
+		f, more := frames.Next()
+		if !internalFrame(f.Function) {
+			return fmt.Sprintf("%s:%d", f.File, f.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// internalFrame checks if the function belongs to one of this module's
+// packages, ignoring tests.
+func internalFrame(fn string) bool {
+	if !strings.HasPrefix(fn, modulePath) {
+		return false
+	}
+	pkg := fn
+	if i := strings.LastIndex(pkg, "/"); i >= 0 {
+		if j := strings.Index(pkg[i:], "."); j >= 0 {
+			pkg = pkg[:i+j]
+		}
+	} else if j := strings.Index(pkg, "."); j >= 0 {
+		pkg = pkg[:j]
+	}
+	return !strings.HasSuffix(pkg, "_test")
+}
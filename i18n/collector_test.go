@@ -0,0 +1,104 @@
+package i18n_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollector(t *testing.T) {
+	c := i18n.NewCollector()
+	d := i18n.NewDict()
+	d.Add("key", "value")
+	ctx := i18n.WithMissingKeyHandler(context.Background(), c.Handle)
+	assert.Equal(t, "!(MISSING LOCALE)", i18n.T(ctx, "orphan"))
+
+	en := i18n.NewLocale("en", d).WithContext(ctx)
+	es := i18n.NewLocale("es", i18n.NewDict()).WithContext(ctx)
+	assert.Equal(t, "value", i18n.T(en, "key"))
+	for i := 0; i < 3; i++ {
+		i18n.T(en, "welcome.title")
+	}
+	i18n.N(en, "welcome.mice", 2)
+	assert.Equal(t, "Welcome", i18n.T(en, "welcome.title", i18n.Default("Welcome")))
+	i18n.T(es, "key")
+	i18n.T(es, "welcome.title")
+
+	list := c.Missing()
+	require.Len(t, list, 5)
+	assert.Equal(t, i18n.MissingKey{Locale: "", Key: "orphan", Hits: 1, Caller: list[0].Caller}, list[0])
+	assert.Equal(t, "welcome.mice", list[1].Key)
+	assert.Equal(t, 4, list[2].Hits)
+	assert.Equal(t, i18n.Code("es"), list[3].Locale)
+	assert.Contains(t, list[2].Caller, "collector_test.go:")
+
+	t.Run("json", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, c.WriteJSON(buf))
+		var out []i18n.MissingKey
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &out))
+		assert.Equal(t, list, out)
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		require.NoError(t, c.WriteYAML(buf))
+		assert.Equal(t, strings.Join([]string{
+			"en:",
+			"  welcome:",
+			"    mice: \"\"",
+			"    title: \"\"",
+			"es:",
+			"  key: \"\"",
+			"  welcome:",
+			"    title: \"\"",
+			"",
+		}, "\n"), buf.String())
+
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.UnmarshalJSON([]byte(`{"en":{"key":"value"}}`)))
+		require.NoError(t, ls.Load(fstest.MapFS{"skeleton.yaml": {Data: buf.Bytes()}}))
+		assert.Equal(t, "value", ls.Get("en").T("key"))
+		assert.True(t, ls.Get("es").Has("welcome.title"))
+	})
+
+	t.Run("chain", func(t *testing.T) {
+		c.Reset()
+		assert.Empty(t, c.Missing())
+		h := i18n.ChainMissingKeyHandlers(c.Handle,
+			func(_ context.Context, _ i18n.Code, key string, _ ...any) (string, bool) {
+				return "[" + key + "]", true
+			},
+			func(_ context.Context, _ i18n.Code, _ string, _ ...any) (string, bool) {
+				return "ignored", true
+			},
+		)
+		ctx := i18n.WithMissingKeyHandler(en, h)
+		assert.Equal(t, "[foo]", i18n.T(ctx, "foo"))
+		assert.Len(t, c.Missing(), 1)
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		c.Reset()
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					i18n.T(en, "busy")
+				}
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, 160, c.Missing()[0].Hits)
+	})
+}
@@ -2,12 +2,16 @@ package i18n
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 )
 
 // Dict holds the internationalization entries for a specific locale.
 type Dict struct {
 	value   string
+	data    any // bool, json.Number, or []*Dict for non-text values
 	entries map[string]*Dict
 }
 
@@ -18,21 +22,57 @@ func NewDict() *Dict {
 	}
 }
 
-// Add adds a new key value pair to the dictionary.
+// Add adds a new key value pair to the dictionary. Besides strings, maps,
+// and other dictionaries, values may also be lists, numbers, or booleans.
+// Any other type of value will be ignored.
 func (d *Dict) Add(key string, value any) {
+	if nd := newDictValue(value); nd != nil {
+		d.entries[key] = nd
+	}
+}
+
+// newDictValue prepares a dictionary containing the provided value, or
+// nil if the type of value is not supported.
+func newDictValue(value any) *Dict {
 	switch v := value.(type) {
 	case string:
-		d.entries[key] = &Dict{value: v}
+		return &Dict{value: v}
 	case map[string]any:
 		nd := NewDict()
 		for k, row := range v {
 			nd.Add(k, row)
 		}
-		d.entries[key] = nd
+		return nd
+	case M:
+		return newDictValue(map[string]any(v))
 	case *Dict:
-		d.entries[key] = v
+		return v
+	case []string:
+		list := make([]*Dict, len(v))
+		for i, row := range v {
+			list[i] = &Dict{value: row}
+		}
+		return &Dict{data: list}
+	case []any:
+		list := make([]*Dict, 0, len(v))
+		for _, row := range v {
+			if nd := newDictValue(row); nd != nil {
+				list = append(list, nd)
+			}
+		}
+		return &Dict{data: list}
+	case bool:
+		return &Dict{value: strconv.FormatBool(v), data: v}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		n := json.Number(fmt.Sprint(v))
+		return &Dict{value: n.String(), data: n}
+	case float32:
+		return newDictValue(float64(v))
+	case float64:
+		n := json.Number(strconv.FormatFloat(v, 'f', -1, 64))
+		return &Dict{value: n.String(), data: n}
 	default:
-		// ignore
+		return nil
 	}
 }
 
@@ -45,6 +85,81 @@ func (d *Dict) Value() string {
 	return d.value
 }
 
+// Keys provides the sorted list of keys defined at the top level of the
+// dictionary, or nil if the dictionary does not contain any entries.
+func (d *Dict) Keys() []string {
+	if d == nil || len(d.entries) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(d.entries))
+	for k := range d.entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// List provides the list of text values, or nil if the dictionary
+// does not contain a list.
+func (d *Dict) List() []string {
+	if d == nil {
+		return nil
+	}
+	list, ok := d.data.([]*Dict)
+	if !ok {
+		return nil
+	}
+	out := make([]string, len(list))
+	for i, row := range list {
+		out[i] = row.Value()
+	}
+	return out
+}
+
+// Int provides the integer value of the dictionary, or zero if the
+// dictionary does not contain a whole number.
+func (d *Dict) Int() int {
+	if d == nil {
+		return 0
+	}
+	n, ok := d.data.(json.Number)
+	if !ok {
+		return 0
+	}
+	i, err := strconv.Atoi(n.String())
+	if err != nil {
+		return 0
+	}
+	return i
+}
+
+// Float provides the numeric value of the dictionary, or zero if the
+// dictionary does not contain a number.
+func (d *Dict) Float() float64 {
+	if d == nil {
+		return 0
+	}
+	n, ok := d.data.(json.Number)
+	if !ok {
+		return 0
+	}
+	f, err := n.Float64()
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// Bool provides the boolean value of the dictionary, or false if the
+// dictionary does not contain a boolean.
+func (d *Dict) Bool() bool {
+	if d == nil {
+		return false
+	}
+	b, _ := d.data.(bool)
+	return b
+}
+
 // Get recursively retrieves the dictionary at the provided key location.
 func (d *Dict) Get(key string) *Dict {
 	if d == nil {
@@ -73,20 +188,172 @@ func (d *Dict) Has(key string) bool {
 
 // Merge combines the entries of the second dictionary into this one. If a
 // key is duplicated in the second diction, the original value takes priority.
+// Entries are copied from the second dictionary, so that changes made later
+// to either dictionary will not affect the other.
 func (d *Dict) Merge(d2 *Dict) {
-	if d2 == nil {
+	d.merge(d2.Clone(), false)
+}
+
+// merge combines the entries of the second dictionary into this one. When
+// overriding, values from the second dictionary will replace any existing
+// values, including when one of the two is a map and the other is not.
+// Entries are not copied, so this should only be used when the second
+// dictionary will not be used again.
+func (d *Dict) merge(d2 *Dict, override bool) {
+	if d2 == nil || d2.entries == nil {
 		return
 	}
 	if d.entries == nil {
 		d.entries = make(map[string]*Dict)
 	}
 	for k, v := range d2.entries {
-		if d.entries[k] == nil {
+		cur := d.entries[k]
+		switch {
+		case cur == nil:
+			d.entries[k] = v
+		case override && (cur.entries == nil || v.entries == nil):
 			d.entries[k] = v
+		default:
+			cur.merge(v, override)
+		}
+	}
+}
+
+// Clone provides a deep copy of the dictionary that can be modified
+// without affecting the original.
+func (d *Dict) Clone() *Dict {
+	if d == nil {
+		return nil
+	}
+	nd := &Dict{
+		value: d.value,
+		data:  d.data,
+	}
+	if list, ok := d.data.([]*Dict); ok {
+		nl := make([]*Dict, len(list))
+		for i, row := range list {
+			nl[i] = row.Clone()
+		}
+		nd.data = nl
+	}
+	if d.entries != nil {
+		nd.entries = make(map[string]*Dict, len(d.entries))
+		for k, v := range d.entries {
+			nd.entries[k] = v.Clone()
+		}
+	}
+	return nd
+}
+
+// Filter provides a copy of the dictionary containing only the entries
+// found under the provided keys, maintaining their full path so that
+// filtering with `login` keeps `login.button`. A copy of the complete
+// dictionary is provided if no keys are given.
+func (d *Dict) Filter(keys ...string) *Dict {
+	if len(keys) == 0 {
+		return d.Clone()
+	}
+	nd := NewDict()
+	for _, k := range keys {
+		v := d.Get(k)
+		if v == nil {
 			continue
 		}
-		d.entries[k].Merge(v)
+		path := strings.Split(k, ".")
+		for i := len(path) - 1; i >= 0; i-- {
+			v = &Dict{entries: map[string]*Dict{path[i]: v}}
+		}
+		nd.Merge(v)
 	}
+	return nd
+}
+
+// Flatten provides a map of all the values in the dictionary using the
+// complete paths as keys, like `login.button`. Lists, numbers, and
+// booleans are kept as they are.
+func (d *Dict) Flatten() map[string]any {
+	out := make(map[string]any)
+	d.flatten("", out)
+	return out
+}
+
+func (d *Dict) flatten(prefix string, out map[string]any) {
+	if d == nil {
+		return
+	}
+	if d.entries == nil {
+		if prefix != "" {
+			out[prefix] = d.export()
+		}
+		return
+	}
+	for k, v := range d.entries {
+		if prefix != "" {
+			k = prefix + "." + k
+		}
+		v.flatten(k, out)
+	}
+}
+
+// MarshalFlatJSON provides the JSON representation of the flattened
+// dictionary, with keys sorted alphabetically.
+func (d *Dict) MarshalFlatJSON() ([]byte, error) {
+	return json.Marshal(d.Flatten())
+}
+
+// MarshalJSON provides the JSON representation of the dictionary, with
+// keys sorted alphabetically.
+func (d *Dict) MarshalJSON() ([]byte, error) {
+	if d == nil {
+		return []byte("null"), nil
+	}
+	if d.entries != nil {
+		return json.Marshal(d.entries)
+	}
+	switch v := d.data.(type) {
+	case []*Dict, bool:
+		return json.Marshal(v)
+	case json.Number:
+		return []byte(v), nil
+	}
+	return json.Marshal(d.value)
+}
+
+// MarshalYAML provides the dictionary's data for YAML encoders that
+// support the Marshaler interface, like `gopkg.in/yaml.v3`.
+func (d *Dict) MarshalYAML() (any, error) {
+	return d.export(), nil
+}
+
+// export converts the dictionary into regular Go types.
+func (d *Dict) export() any {
+	if d == nil {
+		return nil
+	}
+	if d.entries != nil {
+		out := make(map[string]any, len(d.entries))
+		for k, v := range d.entries {
+			out[k] = v.export()
+		}
+		return out
+	}
+	switch v := d.data.(type) {
+	case []*Dict:
+		out := make([]any, len(v))
+		for i, row := range v {
+			out[i] = row.export()
+		}
+		return out
+	case bool:
+		return v
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		}
+		f, _ := v.Float64()
+		return f
+	}
+	return d.value
 }
 
 // UnmarshalJSON attempts to load the dictionary data from a JSON byte slice.
@@ -94,10 +361,34 @@ func (d *Dict) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
-	if data[0] == '"' {
-		d.value = string(data[1 : len(data)-1])
-		return nil
+	switch data[0] {
+	case '"':
+		return json.Unmarshal(data, &d.value)
+	case '{':
+		d.entries = make(map[string]*Dict)
+		return json.Unmarshal(data, &d.entries)
+	case '[':
+		list := make([]*Dict, 0)
+		if err := json.Unmarshal(data, &list); err != nil {
+			return err
+		}
+		d.data = list
+	case 't', 'f':
+		var b bool
+		if err := json.Unmarshal(data, &b); err != nil {
+			return err
+		}
+		d.value = strconv.FormatBool(b)
+		d.data = b
+	case 'n':
+		// null, ignore
+	default:
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return err
+		}
+		d.value = n.String()
+		d.data = n
 	}
-	d.entries = make(map[string]*Dict)
-	return json.Unmarshal(data, &d.entries)
+	return nil
 }
@@ -6,6 +6,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestDictUnmarshalJSON(t *testing.T) {
@@ -35,6 +36,29 @@ func TestDictUnmarshalJSON(t *testing.T) {
 	})
 }
 
+func TestDictMarshalJSON(t *testing.T) {
+	ex := `{"b":{"c":"d \"e\"","a":[1,"x",true]},"a":2.5,"c":false}`
+	d := new(Dict)
+	require.NoError(t, json.Unmarshal([]byte(ex), d))
+	out, err := json.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, `{"a":2.5,"b":{"a":[1,"x",true],"c":"d \"e\""},"c":false}`, string(out))
+
+	var nd *Dict
+	out, err = json.Marshal(nd)
+	require.NoError(t, err)
+	assert.Equal(t, "null", string(out))
+}
+
+func TestDictMarshalYAML(t *testing.T) {
+	ex := `{"b":{"c":"d","a":[1,"x",true]},"a":2.5,"c":false}`
+	d := new(Dict)
+	require.NoError(t, json.Unmarshal([]byte(ex), d))
+	out, err := yaml.Marshal(d)
+	require.NoError(t, err)
+	assert.Equal(t, "a: 2.5\nb:\n    a:\n        - 1\n        - x\n        - true\n    c: d\nc: false\n", string(out))
+}
+
 func TestDictAdd(t *testing.T) {
 	d := NewDict()
 	assert.Nil(t, d.Get(""))
@@ -49,13 +73,99 @@ func TestDictAdd(t *testing.T) {
 	assert.Equal(t, "no mice", d.Get("plural.zero").Value())
 	assert.Equal(t, "%s mice", d.Get("plural.other").Value())
 
-	d.Add("bad", 10) // ignore
+	d.Add("num", 10)
+	assert.Equal(t, 10, d.Get("num").Int())
+	assert.Equal(t, "10", d.Get("num").Value())
+	d.Add("float", 1.5)
+	assert.Equal(t, 1.5, d.Get("float").Float())
+	d.Add("bool", true)
+	assert.True(t, d.Get("bool").Bool())
+	d.Add("list", []string{"a", "b"})
+	assert.Equal(t, []string{"a", "b"}, d.Get("list").List())
+	d.Add("mixed", []any{"a", 1, nil, true})
+	assert.Equal(t, []string{"a", "1", "true"}, d.Get("mixed").List())
+
+	d.Add("bad", struct{}{}) // ignore
 	assert.Nil(t, d.Get("bad"))
 
 	d.Add("self", d)
 	assert.Equal(t, "bar", d.Get("self.foo").Value())
 }
 
+func TestDictTypedValues(t *testing.T) {
+	ex := `{
+		"text": "Hi \"Sam\"",
+		"day_names": ["Sunday", "Monday"],
+		"precision": 2,
+		"ratio": 0.5,
+		"enabled": true,
+		"disabled": false,
+		"empty": null
+	}`
+	d := new(Dict)
+	require.NoError(t, json.Unmarshal([]byte(ex), d))
+	assert.Equal(t, `Hi "Sam"`, d.Get("text").Value())
+	assert.Nil(t, d.Get("text").List())
+	assert.Zero(t, d.Get("text").Int())
+	assert.False(t, d.Get("text").Bool())
+
+	assert.Equal(t, []string{"Sunday", "Monday"}, d.Get("day_names").List())
+	assert.Empty(t, d.Get("day_names").Value())
+
+	assert.Equal(t, 2, d.Get("precision").Int())
+	assert.Equal(t, 2.0, d.Get("precision").Float())
+	assert.Equal(t, "2", d.Get("precision").Value())
+	assert.Zero(t, d.Get("ratio").Int())
+	assert.Equal(t, 0.5, d.Get("ratio").Float())
+
+	assert.True(t, d.Get("enabled").Bool())
+	assert.Equal(t, "true", d.Get("enabled").Value())
+	assert.False(t, d.Get("disabled").Bool())
+	assert.True(t, d.Has("disabled"))
+	assert.Empty(t, d.Get("empty").Value())
+
+	var nd *Dict
+	assert.Nil(t, nd.List())
+	assert.Zero(t, nd.Int())
+	assert.Zero(t, nd.Float())
+	assert.False(t, nd.Bool())
+
+	t.Run("invalid", func(t *testing.T) {
+		d := new(Dict)
+		assert.Error(t, d.UnmarshalJSON([]byte(`[1,`)))
+		assert.Error(t, d.UnmarshalJSON([]byte(`tru`)))
+		assert.Error(t, d.UnmarshalJSON([]byte(`1x`)))
+	})
+}
+
+func TestDictClone(t *testing.T) {
+	d := new(Dict)
+	require.NoError(t, json.Unmarshal([]byte(`{"foo":"bar","baz":{"qux":"quux"},"list":["a",{"b":"c"}],"num":1}`), d))
+	c := d.Clone()
+	assert.Equal(t, d, c)
+
+	c.Get("baz").Add("new", "value")
+	c.Get("list").data.([]*Dict)[1].Add("d", "e")
+	assert.False(t, d.Has("baz.new"))
+	assert.False(t, d.Get("list").data.([]*Dict)[1].Has("d"))
+	assert.True(t, c.Has("baz.new"))
+
+	var nd *Dict
+	assert.Nil(t, nd.Clone())
+}
+
+func TestDictKeys(t *testing.T) {
+	d := NewDict()
+	assert.Nil(t, d.Keys())
+	d.Add("foo", "bar")
+	d.Add("baz", map[string]any{"qux": "quux"})
+	d.Add("abc", "def")
+	assert.Equal(t, []string{"abc", "baz", "foo"}, d.Keys())
+	assert.Equal(t, []string{"qux"}, d.Get("baz").Keys())
+	assert.Nil(t, d.Get("foo").Keys())
+	assert.Nil(t, d.Get("random").Keys())
+}
+
 func TestDictHas(t *testing.T) {
 	t.Run("simple case", func(t *testing.T) {
 		d := NewDict()
@@ -109,5 +219,63 @@ func TestDictMerge(t *testing.T) {
 
 	d1.Merge(d2)
 	assert.Equal(t, "bar", d1.Get("foo").Value(), "should not overwrite")
+	assert.Nil(t, d1.Get("foo").entries, "leaves remain leaves")
 	assert.Equal(t, "value", d1.Get("extra").Value())
+
+	t.Run("copies entries", func(t *testing.T) {
+		d4 := NewDict()
+		d4.Merge(d1)
+		d4.Get("baz").Add("new", "value")
+		d4.Get("baz.plural").Add("two", "%s mice")
+		assert.False(t, d1.Has("baz.new"))
+		assert.False(t, d1.Has("baz.plural.two"))
+		d1.Get("baz").Add("other", "value")
+		assert.False(t, d4.Has("baz.other"))
+	})
+	assert.Equal(t, "value", d1.Get("extra").Value())
+}
+
+func TestDictFilter(t *testing.T) {
+	d := NewDict()
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"login": {"button": "Log In", "title": "Welcome"},
+		"date": {"day_names": ["Sun", "Mon"], "formats": {"short": "%d/%m"}},
+		"number": {"precision": 2}
+	}`), d))
+
+	f := d.Filter("login", "date.formats", "bad", "number.precision.bad")
+	assert.Equal(t, []string{"date", "login"}, f.Keys())
+	assert.Equal(t, "Log In", f.Get("login.button").Value())
+	assert.Equal(t, "%d/%m", f.Get("date.formats.short").Value())
+	assert.False(t, f.Has("date.day_names"))
+
+	f.Add("extra", "value")
+	f.Get("login").Add("button", "Changed")
+	assert.Equal(t, "Log In", d.Get("login.button").Value(), "original unchanged")
+	assert.False(t, d.Has("extra"))
+
+	assert.Equal(t, d.Flatten(), d.Filter().Flatten())
+}
+
+func TestDictFlatten(t *testing.T) {
+	d := NewDict()
+	require.NoError(t, json.Unmarshal([]byte(`{
+		"login": {"button": "Log In"},
+		"date": {"day_names": ["Sun", "Mon"]},
+		"number": {"precision": 2, "grouping": true}
+	}`), d))
+
+	assert.Equal(t, map[string]any{
+		"login.button":     "Log In",
+		"date.day_names":   []any{"Sun", "Mon"},
+		"number.precision": int64(2),
+		"number.grouping":  true,
+	}, d.Flatten())
+
+	data, err := d.MarshalFlatJSON()
+	require.NoError(t, err)
+	assert.Equal(t, `{"date.day_names":["Sun","Mon"],"login.button":"Log In","number.grouping":true,"number.precision":2}`, string(data))
+
+	var nd *Dict
+	assert.Empty(t, nd.Flatten())
 }
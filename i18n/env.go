@@ -0,0 +1,64 @@
+package i18n
+
+import (
+	"os"
+	"strings"
+)
+
+// ParsePOSIXLocale converts a POSIX locale name, as used in environment
+// variables like `LANG`, into a Code, so that `pt_BR.UTF-8@euro` becomes
+// `pt-BR`. The special `C` and `POSIX` locales, which imply that texts
+// should not be translated, provide an empty code.
+func ParsePOSIXLocale(s string) Code {
+	if i := strings.IndexAny(s, ".@"); i >= 0 {
+		s = s[:i]
+	}
+	switch s {
+	case "", "C", "POSIX":
+		return ""
+	}
+	return Code(strings.ReplaceAll(s, "_", "-"))
+}
+
+// EnvCodes determines the preferred locales from the environment following
+// the same rules as gettext: the locale is taken from the first of `LC_ALL`,
+// `LC_MESSAGES` or `LANG` that is set, but may be replaced by the colon
+// separated list of locales in `LANGUAGE`, unless it is `C` or `POSIX`.
+// The base language is included after each code with a region, so that
+// `pt_BR` provides both `pt-BR` and `pt`.
+func EnvCodes() []Code {
+	var locale string
+	for _, k := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		if v := os.Getenv(k); v != "" {
+			locale = v
+			break
+		}
+	}
+	main := ParsePOSIXLocale(locale)
+	if main == "" {
+		return nil
+	}
+
+	var codes []Code
+	add := func(c Code) {
+		for _, ex := range []Code{c, c.Base()} {
+			if ex != "" && !hasCode(codes, ex) {
+				codes = append(codes, ex)
+			}
+		}
+	}
+	for _, s := range strings.Split(os.Getenv("LANGUAGE"), ":") {
+		add(ParsePOSIXLocale(s))
+	}
+	add(main)
+	return codes
+}
+
+func hasCode(list []Code, c Code) bool {
+	for _, v := range list {
+		if v == c {
+			return true
+		}
+	}
+	return false
+}
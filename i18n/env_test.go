@@ -0,0 +1,55 @@
+package i18n_test
+
+import (
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePOSIXLocale(t *testing.T) {
+	tests := map[string]i18n.Code{
+		"pt_BR.UTF-8@euro": "pt-BR",
+		"pt_BR.UTF-8":      "pt-BR",
+		"de_DE@euro":       "de-DE",
+		"en_US":            "en-US",
+		"es":               "es",
+		"C":                "",
+		"C.UTF-8":          "",
+		"POSIX":            "",
+		"":                 "",
+	}
+	for in, want := range tests {
+		assert.Equal(t, want, i18n.ParsePOSIXLocale(in), in)
+	}
+}
+
+func setEnv(t *testing.T, vars map[string]string) {
+	t.Helper()
+	for _, k := range []string{"LANGUAGE", "LC_ALL", "LC_MESSAGES", "LANG"} {
+		t.Setenv(k, vars[k])
+	}
+}
+
+func TestEnvCodes(t *testing.T) {
+	tests := []struct {
+		name string
+		vars map[string]string
+		want []i18n.Code
+	}{
+		{"empty", nil, nil},
+		{"lang", map[string]string{"LANG": "pt_BR.UTF-8"}, []i18n.Code{"pt-BR", "pt"}},
+		{"messages", map[string]string{"LANG": "en_US.UTF-8", "LC_MESSAGES": "es_ES.UTF-8"}, []i18n.Code{"es-ES", "es"}},
+		{"all", map[string]string{"LANG": "en_US", "LC_MESSAGES": "es_ES", "LC_ALL": "fr_FR"}, []i18n.Code{"fr-FR", "fr"}},
+		{"language", map[string]string{"LANG": "en_US.UTF-8", "LANGUAGE": "es_MX:pt:en"}, []i18n.Code{"es-MX", "es", "pt", "en", "en-US"}},
+		{"language without locale", map[string]string{"LANGUAGE": "es"}, nil},
+		{"c locale", map[string]string{"LANG": "C.UTF-8", "LANGUAGE": "es"}, nil},
+		{"posix override", map[string]string{"LANG": "es_ES", "LC_ALL": "POSIX"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setEnv(t, tt.vars)
+			assert.Equal(t, tt.want, i18n.EnvCodes())
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package i18n
+
+import (
+	"context"
+	"errors"
+)
+
+// Error is an error whose message will be translated only when needed, so
+// that it can be created before the user's locale is known. Errors with
+// the same key are considered equal by `errors.Is`, so they may also be
+// used as sentinel errors.
+type Error struct {
+	// Key of the translation for the message.
+	Key string `json:"key"`
+	// Args used to interpolate the message, if any.
+	Args M `json:"args,omitempty"`
+	// Err is the underlying cause, if any.
+	Err error `json:"-"`
+	// Default is the locale used to render the message when no other
+	// locale is available, as set by the errors prepared by a Bundle.
+	Default *Locale `json:"-"`
+}
+
+// NewError prepares a new error with the key and optional arguments for
+// the message.
+func NewError(key string, args M) *Error {
+	return &Error{Key: key, Args: args}
+}
+
+// WrapError prepares a new error with the key and optional arguments for
+// the message, and the cause.
+func WrapError(err error, key string, args M) *Error {
+	return &Error{Key: key, Args: args, Err: err}
+}
+
+// Error provides the message rendered in the default locale followed by
+// the cause, if any. The key is used instead of the message if there is no
+// default locale or the key is not defined in it.
+func (e *Error) Error() string {
+	msg := e.message(e.Default)
+	if e.Err != nil {
+		return msg + ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Localize renders the message in the locale stored in the context, or in
+// the default locales if the key is not defined, in the same way as
+// Localized. The cause is not included, as it will not usually be
+// translated.
+func (e *Error) Localize(ctx context.Context) string {
+	return e.message(GetLocale(ctx), GetDefaultLocale(ctx), e.Default)
+}
+
+// Unwrap provides the cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is checks if the target is an Error with the same key.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && t.Key == e.Key
+}
+
+// LocalizedError contains the key and arguments of an Error along with
+// its message in a specific locale, ready to be included in API responses.
+type LocalizedError struct {
+	Key     string `json:"key"`
+	Args    M      `json:"args,omitempty"`
+	Message string `json:"message"`
+}
+
+// Localized prepares the error with the message in the locale stored in
+// the context, or in the context's or error's default locale if the key is
+// not defined, falling back to the key. The cause is not included so that
+// internal details are not leaked.
+func (e *Error) Localized(ctx context.Context) *LocalizedError {
+	return &LocalizedError{
+		Key:     e.Key,
+		Args:    e.Args,
+		Message: e.message(GetLocale(ctx), GetDefaultLocale(ctx), e.Default),
+	}
+}
+
+// message provides the text from the first locale that defines the key.
+func (e *Error) message(locales ...*Locale) string {
+	for _, l := range locales {
+		if l == nil {
+			continue
+		}
+		if s, err := l.TE(e.Key, e.args()...); err == nil {
+			return s
+		}
+	}
+	return e.Key
+}
+
+func (e *Error) args() []any {
+	if e.Args == nil {
+		return nil
+	}
+	return []any{e.Args}
+}
+
+// Error renders the message of the error in the locale, or the error's
+// default locale, if it is, or wraps, an `Error`, or provides the regular
+// error message otherwise. An empty string is provided for a nil error.
+func (l *Locale) Error(err error) string {
+	if err == nil {
+		return ""
+	}
+	var e *Error
+	if errors.As(err, &e) {
+		return e.message(l, e.Default)
+	}
+	return err.Error()
+}
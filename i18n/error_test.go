@@ -0,0 +1,91 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func errorLocales(t *testing.T) *i18n.Locales {
+	t.Helper()
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.UnmarshalJSON([]byte(`{
+		"en": {"errors": {"not_found": "%{item} not found", "denied": "Access denied"}},
+		"es": {"errors": {"not_found": "%{item} no encontrado", "denied": "Acceso denegado"}}
+	}`)))
+	return ls
+}
+
+func TestError(t *testing.T) {
+	ls := errorLocales(t)
+	cause := errors.New("sql: no rows")
+	err := i18n.WrapError(cause, "errors.not_found", i18n.M{"item": "Invoice"})
+
+	assert.Equal(t, "errors.not_found: sql: no rows", err.Error())
+	assert.Equal(t, "errors.denied", i18n.NewError("errors.denied", nil).Error())
+
+	ctx := ls.Get("es").WithContext(context.Background())
+	assert.Equal(t, "Invoice no encontrado", err.Localize(ctx))
+	assert.Equal(t, "errors.not_found", err.Localize(context.Background()))
+
+	t.Run("default", func(t *testing.T) {
+		e := i18n.WrapError(cause, "errors.not_found", i18n.M{"item": "Invoice"})
+		e.Default = ls.Get("en")
+		assert.Equal(t, "Invoice not found: sql: no rows", e.Error())
+		assert.Equal(t, "Invoice no encontrado", e.Localize(ctx))
+		assert.Equal(t, "Invoice not found", e.Localize(context.Background()))
+		assert.Equal(t, "Invoice not found", i18n.NewLocale("fr", i18n.NewDict()).Error(e))
+
+		u := &i18n.Error{Key: "errors.unknown", Default: ls.Get("en")}
+		assert.Equal(t, "errors.unknown", u.Error())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		wrapped := fmt.Errorf("loading: %w", err)
+		assert.ErrorIs(t, wrapped, cause)
+		assert.ErrorIs(t, wrapped, i18n.NewError("errors.not_found", nil))
+		assert.NotErrorIs(t, wrapped, i18n.NewError("errors.denied", nil))
+		var none *i18n.Error
+		assert.False(t, err.Is(none))
+
+		var e *i18n.Error
+		require.ErrorAs(t, wrapped, &e)
+		assert.Equal(t, "errors.not_found", e.Key)
+	})
+
+	t.Run("locale", func(t *testing.T) {
+		es := ls.Get("es")
+		assert.Equal(t, "Invoice no encontrado", es.Error(fmt.Errorf("loading: %w", err)))
+		assert.Equal(t, "Acceso denegado", es.Error(i18n.NewError("errors.denied", nil)))
+		assert.Equal(t, "sql: no rows", es.Error(cause))
+		assert.Equal(t, "", es.Error(nil))
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, jerr := json.Marshal(err)
+		require.NoError(t, jerr)
+		assert.JSONEq(t, `{"key":"errors.not_found","args":{"item":"Invoice"}}`, string(data))
+
+		e := new(i18n.Error)
+		require.NoError(t, json.Unmarshal(data, e))
+		assert.Equal(t, "Invoice no encontrado", e.Localize(ctx))
+	})
+
+	t.Run("localized", func(t *testing.T) {
+		data, jerr := json.Marshal(err.Localized(ctx))
+		require.NoError(t, jerr)
+		assert.JSONEq(t, `{"key":"errors.not_found","args":{"item":"Invoice"},"message":"Invoice no encontrado"}`, string(data))
+
+		fr := i18n.NewLocale("fr", i18n.NewDict())
+		ctx := i18n.WithDefaultLocale(fr.WithContext(context.Background()), ls.Get("en"))
+		assert.Equal(t, "Access denied", i18n.NewError("errors.denied", nil).Localized(ctx).Message, "default locale")
+		assert.Equal(t, "errors.unknown", i18n.NewError("errors.unknown", nil).Localized(ctx).Message)
+		assert.Equal(t, "errors.denied", i18n.NewError("errors.denied", nil).Localized(context.Background()).Message)
+	})
+}
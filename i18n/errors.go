@@ -0,0 +1,182 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Translation errors provided by the `TE` and `NE` methods.
+var (
+	// ErrMissingKey is used when the key is not defined in the locale.
+	ErrMissingKey = errors.New("missing key")
+	// ErrMissingLocale is used when there is no locale in the context.
+	ErrMissingLocale = errors.New("locale not defined")
+	// ErrMissingPluralForm is used when the key is defined, but does not
+	// contain a text for the count.
+	ErrMissingPluralForm = errors.New("missing plural form")
+	// ErrInterpolation is used when the arguments could not be inserted
+	// into the text, for example when a placeholder has no value.
+	ErrInterpolation = errors.New("interpolation failed")
+)
+
+var placeholderRegexp = regexp.MustCompile(`%\{[^}]+\}`)
+
+// translate prepares the text in the same way as `format`, but reports
+// any problems found in the process. The text is checked before the
+// arguments are inserted, so values containing `%!` or `%{` are fine.
+func translate(key string, d *Dict, args ...any) (string, error) {
+	var s string
+	s, args = extractDefault(args)
+	if d != nil {
+		s = d.value
+	}
+	if s == "" {
+		return "", fmt.Errorf("%w: %s", ErrMissingKey, key)
+	}
+	var m M
+	if len(args) > 0 {
+		m, _ = args[0].(M)
+	}
+	if p := missingPlaceholder(s, m); p != "" {
+		return "", fmt.Errorf("%w: %s: no value for %s", ErrInterpolation, key, p)
+	}
+	if len(args) == 0 {
+		return s, nil
+	}
+	if m != nil {
+		return m.Replace(s), nil
+	}
+	if err := checkVerbs(s, args); err != nil {
+		return "", fmt.Errorf("%w: %s: %s", ErrInterpolation, key, err.Error())
+	}
+	return fmt.Sprintf(s, args...), nil
+}
+
+// missingPlaceholder provides the first `%{key}` placeholder in the text
+// without a value in the map.
+func missingPlaceholder(s string, m M) string {
+	for _, p := range placeholderRegexp.FindAllString(s, -1) {
+		if _, ok := m[p[2:len(p)-1]]; !ok {
+			return p
+		}
+	}
+	return ""
+}
+
+// checkVerbs ensures the fmt style verbs in the text, including any
+// explicit argument indexes and `*` widths or precisions, match the
+// arguments provided, so that `fmt.Sprintf` will not report an error.
+func checkVerbs(s string, args []any) error {
+	n := 0
+	reordered := false
+	index := func(i int) (int, error) {
+		if i >= len(s) || s[i] != '[' {
+			return i, nil
+		}
+		j := strings.IndexByte(s[i:], ']')
+		if j < 0 {
+			return i, errors.New("bad argument index")
+		}
+		k, err := strconv.Atoi(s[i+1 : i+j])
+		if err != nil || k < 1 {
+			return i, errors.New("bad argument index")
+		}
+		n = k - 1
+		reordered = true
+		return i + j + 1, nil
+	}
+	star := func(i int, spec string) (int, error) {
+		if i >= len(s) || s[i] != '*' {
+			for i < len(s) && s[i] >= '0' && s[i] <= '9' {
+				i++
+			}
+			return i, nil
+		}
+		if n >= len(args) {
+			return i, errors.New("missing argument for *")
+		}
+		if out := fmt.Sprintf(spec, args[n], 0); strings.HasPrefix(out, "%!") {
+			return i, fmt.Errorf("invalid argument for *: %v", args[n])
+		}
+		n++
+		return i + 1, nil
+	}
+
+	var err error
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' {
+			continue
+		}
+		start := i
+		i++
+		for i < len(s) && strings.IndexByte("#0+- ", s[i]) >= 0 {
+			i++
+		}
+		if i, err = index(i); err != nil {
+			return err
+		}
+		if i, err = star(i, "%*d"); err != nil {
+			return err
+		}
+		if i < len(s) && s[i] == '.' {
+			if i, err = index(i + 1); err != nil {
+				return err
+			}
+			if i, err = star(i, "%.*d"); err != nil {
+				return err
+			}
+		}
+		if i, err = index(i); err != nil {
+			return err
+		}
+		if i >= len(s) {
+			return errors.New("missing verb")
+		}
+		verb, size := utf8.DecodeRuneInString(s[i:])
+		spec := s[start : i+size]
+		i += size - 1
+		if verb == '%' {
+			continue
+		}
+		if n >= len(args) {
+			return fmt.Errorf("missing argument for %s", spec)
+		}
+		if badVerb(verb, args[n]) {
+			return fmt.Errorf("invalid argument for %s: %v", spec, args[n])
+		}
+		n++
+	}
+	if !reordered && n < len(args) {
+		return fmt.Errorf("unused arguments: %v", args[n:])
+	}
+	return nil
+}
+
+// badVerb checks if fmt would report the verb as invalid for the value's
+// type, like `%d` with a string.
+func badVerb(verb rune, v any) bool {
+	prefix := "%!" + string(verb) + "("
+	if v == nil {
+		prefix += "<nil>)"
+	} else {
+		prefix += reflect.TypeOf(v).String() + "="
+	}
+	return strings.HasPrefix(fmt.Sprintf("%"+string(verb), v), prefix)
+}
+
+// translatePlural prepares the plural form found for the count, reporting
+// if the key is defined but the form is not.
+func translatePlural(key string, d, form *Dict, n int, args ...any) (string, error) {
+	if d == nil {
+		return translate(key, nil, args...)
+	}
+	if form == nil {
+		return "", fmt.Errorf("%w: %s: %d", ErrMissingPluralForm, key, n)
+	}
+	return translate(key, form, args...)
+}
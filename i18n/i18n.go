@@ -25,23 +25,62 @@ type M map[string]any
 // T is responsible for translating a key into a string by extracting
 // the local from the context.
 func T(ctx context.Context, key string, args ...any) string {
+	key = ExpandKey(ctx, key)
 	l := GetLocale(ctx)
 	if l == nil {
-		return missingLocaleOut
+		return handleMissing(ctx, "", key, missingLocaleOut, args)
 	}
-	key = ExpandKey(ctx, key)
-	return l.T(key, args...)
+	return translated(ctx, l, key, l.get(key), args)
 }
 
 // N returns the pluralized translation of the provided key using n
 // as the count.
 func N(ctx context.Context, key string, n int, args ...any) string {
+	key = ExpandKey(ctx, key)
+	l := GetLocale(ctx)
+	if l == nil {
+		return handleMissing(ctx, "", key, missingLocaleOut, args)
+	}
+	return translated(ctx, l, key, l.plural(key, n), args)
+}
+
+// translated formats the entry found for the key. Entries not defined in
+// the locale are always reported to the missing key handler, even when a
+// default text is provided, in which case the default is used.
+func translated(ctx context.Context, l *Locale, key string, d *Dict, args []any) string {
+	if d == nil {
+		out := handleMissing(ctx, l.code, key, missing(key), args)
+		if s, ok := format(nil, args...); ok {
+			return s
+		}
+		return out
+	}
+	if s, ok := format(d, args...); ok {
+		return s
+	}
+	return handleMissing(ctx, l.code, key, missing(key), args)
+}
+
+// TE translates the key like T, but returns an error if there is no
+// locale in the context or the text could not be prepared.
+func TE(ctx context.Context, key string, args ...any) (string, error) {
 	l := GetLocale(ctx)
 	if l == nil {
-		return missingLocaleOut
+		return "", ErrMissingLocale
 	}
 	key = ExpandKey(ctx, key)
-	return l.N(key, n, args...)
+	return l.TE(key, args...)
+}
+
+// NE returns the pluralized translation like N, but returns an error if
+// there is no locale in the context or the text could not be prepared.
+func NE(ctx context.Context, key string, n int, args ...any) (string, error) {
+	l := GetLocale(ctx)
+	if l == nil {
+		return "", ErrMissingLocale
+	}
+	key = ExpandKey(ctx, key)
+	return l.NE(key, n, args...)
 }
 
 // Has performs a check to see if the key exists in the locale.
@@ -54,6 +93,50 @@ func Has(ctx context.Context, key string) bool {
 	return l.Has(key)
 }
 
+// List provides the list of texts defined for the key in the locale
+// stored in the context.
+func List(ctx context.Context, key string) []string {
+	l := GetLocale(ctx)
+	if l == nil {
+		return nil
+	}
+	key = ExpandKey(ctx, key)
+	return l.List(key)
+}
+
+// Int provides the whole number defined for the key in the locale
+// stored in the context.
+func Int(ctx context.Context, key string) int {
+	l := GetLocale(ctx)
+	if l == nil {
+		return 0
+	}
+	key = ExpandKey(ctx, key)
+	return l.Int(key)
+}
+
+// Float provides the number defined for the key in the locale
+// stored in the context.
+func Float(ctx context.Context, key string) float64 {
+	l := GetLocale(ctx)
+	if l == nil {
+		return 0
+	}
+	key = ExpandKey(ctx, key)
+	return l.Float(key)
+}
+
+// Bool provides the boolean defined for the key in the locale
+// stored in the context.
+func Bool(ctx context.Context, key string) bool {
+	l := GetLocale(ctx)
+	if l == nil {
+		return false
+	}
+	key = ExpandKey(ctx, key)
+	return l.Bool(key)
+}
+
 // WithScope is used to add a new scope to the context. To use this,
 // use a `.` at the beginning of keys.
 func WithScope(ctx context.Context, key string) context.Context {
@@ -48,6 +48,34 @@ func TestN(t *testing.T) {
 	assert.Equal(t, "2 mice", i18n.N(ctx, "key", 2, i18n.M{"count": 2}))
 }
 
+func TestTE(t *testing.T) {
+	ctx := context.Background()
+	_, err := i18n.TE(ctx, "key")
+	assert.ErrorIs(t, err, i18n.ErrMissingLocale)
+	_, err = i18n.NE(ctx, "key", 1)
+	assert.ErrorIs(t, err, i18n.ErrMissingLocale)
+
+	d := i18n.NewDict()
+	d.Add("scope", map[string]any{
+		"key":   "value %{name}",
+		"count": map[string]any{"one": "one", "other": "many"},
+	})
+	ctx = i18n.WithScope(i18n.NewLocale("en", d).WithContext(ctx), "scope")
+
+	out, err := i18n.TE(ctx, ".key", i18n.M{"name": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "value test", out)
+	_, err = i18n.TE(ctx, ".bad")
+	assert.ErrorIs(t, err, i18n.ErrMissingKey)
+	assert.ErrorContains(t, err, "scope.bad")
+
+	out, err = i18n.NE(ctx, ".count", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "many", out)
+	_, err = i18n.NE(ctx, ".bad", 2)
+	assert.ErrorIs(t, err, i18n.ErrMissingKey)
+}
+
 func TestHas(t *testing.T) {
 	d := i18n.NewDict()
 	d.Add("key", "value")
@@ -60,6 +88,25 @@ func TestHas(t *testing.T) {
 	assert.False(t, i18n.Has(ctx, "key"))
 }
 
+func TestTypedValues(t *testing.T) {
+	ctx := context.Background()
+	assert.Nil(t, i18n.List(ctx, "date.day_names"))
+	assert.Zero(t, i18n.Int(ctx, "number.precision"))
+	assert.Zero(t, i18n.Float(ctx, "number.ratio"))
+	assert.False(t, i18n.Bool(ctx, "number.grouping"))
+
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+	ctx = l.WithContext(ctx)
+	assert.Equal(t, []string{"Sunday", "Monday", "Tuesday"}, i18n.List(ctx, "date.day_names"))
+	assert.Equal(t, 2, i18n.Int(ctx, "number.precision"))
+	assert.Equal(t, 0.5, i18n.Float(ctx, "number.ratio"))
+	assert.True(t, i18n.Bool(ctx, "number.grouping"))
+
+	ctx = i18n.WithScope(ctx, "number")
+	assert.Equal(t, 2, i18n.Int(ctx, ".precision"))
+}
+
 func TestScopes(t *testing.T) {
 	in := SampleLocaleData()
 	l := i18n.NewLocale("en", nil)
@@ -0,0 +1,151 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+)
+
+// Layer defines a named source of locale files. When multiple layers
+// are loaded, entries from layers with a higher precedence will replace
+// those from layers with a lower precedence.
+type Layer struct {
+	// Name used to identify the layer.
+	Name string
+	// Src is the File System to load the files from.
+	Src fs.FS
+	// Options used when loading the files.
+	Options []LoadOption
+}
+
+// layer keeps a Layer with the locales loaded from it.
+type layer struct {
+	Layer
+	locales *Locales
+}
+
+// LoadLayers replaces any existing layers with the provided ordered list,
+// from lowest to highest precedence, so that the last layer wins when the
+// same key is defined in multiple layers. Locales loaded directly with Load
+// always have the lowest precedence. The current layers will be kept if any
+// of the new layers fails to load.
+func (ls *Locales) LoadLayers(layers ...Layer) error {
+	list := make([]*layer, len(layers))
+	for i, l := range layers {
+		if findLayer(list[:i], l.Name) != nil {
+			return fmt.Errorf("duplicate layer: %s", l.Name)
+		}
+		nl, err := loadLayer(l)
+		if err != nil {
+			return err
+		}
+		list[i] = nl
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.layers = list
+	ls.rebuild()
+	return nil
+}
+
+// ReplaceLayer loads the files for the provided layer and replaces the
+// existing layer with the same name, maintaining its precedence.
+func (ls *Locales) ReplaceLayer(l Layer) error {
+	ls.mu.RLock()
+	cur := findLayer(ls.layers, l.Name)
+	ls.mu.RUnlock()
+	if cur == nil {
+		return fmt.Errorf("undefined layer: %s", l.Name)
+	}
+	nl, err := loadLayer(l)
+	if err != nil {
+		return err
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	for i, cur := range ls.layers {
+		if cur.Name != l.Name {
+			continue
+		}
+		list := make([]*layer, len(ls.layers))
+		copy(list, ls.layers)
+		list[i] = nl
+		ls.layers = list
+		ls.rebuild()
+		return nil
+	}
+	return fmt.Errorf("undefined layer: %s", l.Name)
+}
+
+// Layers provides the names of the layers currently loaded, ordered from
+// lowest to highest precedence.
+func (ls *Locales) Layers() []string {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	names := make([]string, len(ls.layers))
+	for i, l := range ls.layers {
+		names[i] = l.Name
+	}
+	return names
+}
+
+func loadLayer(l Layer) (*layer, error) {
+	nl := &layer{
+		Layer:   l,
+		locales: new(Locales),
+	}
+	if err := nl.locales.Load(l.Src, l.Options...); err != nil {
+		return nil, fmt.Errorf("layer %s: %w", l.Name, err)
+	}
+	return nl, nil
+}
+
+func findLayer(list []*layer, name string) *layer {
+	for _, l := range list {
+		if l.Name == name {
+			return l
+		}
+	}
+	return nil
+}
+
+// rebuild prepares the list of available locales by merging copies of the
+// dictionaries from each layer in order of precedence, followed by the
+// locales loaded directly. Without layers, the directly loaded locales
+// are used as is. Merged locales from a previous build are reused so that
+// any references to them will see the new dictionaries. The caller must
+// hold the write lock.
+func (ls *Locales) rebuild() {
+	if len(ls.layers) == 0 {
+		ls.list = ls.base
+		return
+	}
+	ds := newDicts()
+	add := func(src []*Locale) {
+		for _, l := range src {
+			d := l.Dict().Clone()
+			if cur, ok := ds.index[l.code]; ok {
+				cur.merge(d, false)
+				continue
+			}
+			ds.merge(l.code, d, false)
+		}
+	}
+	for i := len(ls.layers) - 1; i >= 0; i-- {
+		add(ls.layers[i].locales.list)
+	}
+	add(ls.base)
+
+	list := make([]*Locale, len(ds.codes))
+	for i, c := range ds.codes {
+		l := findLocale(ls.list, c)
+		if l == nil || findLocale(ls.base, c) == l {
+			list[i] = NewLocale(c, ds.index[c])
+			continue
+		}
+		l.dict.Store(ds.index[c])
+		list[i] = l
+	}
+	ls.list = list
+}
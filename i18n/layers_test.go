@@ -0,0 +1,104 @@
+package i18n_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalesLoadLayers(t *testing.T) {
+	override := fstest.MapFS{
+		"en.yaml": {Data: []byte("en:\n  login:\n    button: \"Sign In\"\n  extra: \"Extra\"\n")},
+		"fr.yaml": {Data: []byte("fr:\n  login:\n    button: \"Connexion\"\n")},
+	}
+
+	ls := new(i18n.Locales)
+	err := ls.LoadLayers(
+		i18n.Layer{Name: "embedded", Src: examples.Content},
+		i18n.Layer{Name: "override", Src: override},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"embedded", "override"}, ls.Layers())
+	assert.ElementsMatch(t, []i18n.Code{"en", "es", "fr"}, ls.Codes())
+
+	en := ls.Get("en")
+	require.NotNil(t, en)
+	assert.Equal(t, "Sign In", en.T("login.button"))
+	assert.Equal(t, "Sign Up", en.T("login.signup-button"))
+	assert.Equal(t, "Extra", en.T("extra"))
+	assert.Equal(t, "Iniciar Sesión", ls.Get("es").T("login.button"))
+	assert.Equal(t, "Connexion", ls.Get("fr").T("login.button"))
+
+	t.Run("replace", func(t *testing.T) {
+		err := ls.ReplaceLayer(i18n.Layer{
+			Name: "override",
+			Src: fstest.MapFS{
+				"en.yaml": {Data: []byte("en:\n  login:\n    button: \"Enter\"\n")},
+			},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"embedded", "override"}, ls.Layers())
+		assert.Equal(t, "Enter", ls.Get("en").T("login.button"))
+		assert.False(t, ls.Get("en").Has("extra"))
+		assert.Nil(t, ls.Get("fr"))
+		assert.Equal(t, "Enter", en.T("login.button"), "existing locale updated")
+
+		err = ls.ReplaceLayer(i18n.Layer{Name: "embedded", Src: examples.Content})
+		require.NoError(t, err)
+		assert.Equal(t, "Enter", ls.Get("en").T("login.button"), "precedence maintained")
+	})
+
+	t.Run("replace errors", func(t *testing.T) {
+		err := ls.ReplaceLayer(i18n.Layer{Name: "bad", Src: override})
+		assert.ErrorContains(t, err, "undefined layer: bad")
+
+		err = ls.ReplaceLayer(i18n.Layer{
+			Name: "override",
+			Src: fstest.MapFS{
+				"en.yaml": {Data: []byte("en: [bad\n")},
+			},
+		})
+		assert.ErrorContains(t, err, "layer override: unmarshalling file 'en.yaml'")
+		assert.Equal(t, "Enter", ls.Get("en").T("login.button"))
+	})
+
+	t.Run("with direct loads", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(fstest.MapFS{
+			"en.yaml": {Data: []byte("en:\n  login:\n    button: \"Base\"\n  base: \"Base\"\n")},
+		}))
+		assert.Equal(t, "Base", ls.Get("en").T("login.button"))
+		err := ls.LoadLayers(i18n.Layer{Name: "embedded", Src: examples.Content})
+		require.NoError(t, err)
+		assert.Equal(t, "Log In", ls.Get("en").T("login.button"))
+		assert.Equal(t, "Base", ls.Get("en").T("base"))
+
+		require.NoError(t, ls.Load(fstest.MapFS{
+			"en.yaml": {Data: []byte("en:\n  another: \"Another\"\n")},
+		}))
+		assert.Equal(t, "Another", ls.Get("en").T("another"))
+
+		require.NoError(t, ls.LoadLayers())
+		assert.Empty(t, ls.Layers())
+		assert.Equal(t, "Base", ls.Get("en").T("login.button"))
+	})
+
+	t.Run("load errors", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		err := ls.LoadLayers(
+			i18n.Layer{Name: "embedded", Src: examples.Content},
+			i18n.Layer{Name: "embedded", Src: override},
+		)
+		assert.ErrorContains(t, err, "duplicate layer: embedded")
+
+		err = ls.LoadLayers(
+			i18n.Layer{Name: "inferred", Src: override, Options: []i18n.LoadOption{i18n.NamespaceFromFile()}},
+		)
+		require.NoError(t, err)
+		assert.Equal(t, "Sign In", ls.Get("en").T("en.login.button"))
+	})
+}
@@ -4,18 +4,37 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 )
 
 // Locale holds the internationalization entries for a specific locale.
+// The dictionary is replaced atomically when new entries are loaded, so
+// locales are safe to use while loading.
 type Locale struct {
 	code Code
-	dict *Dict
+	dict atomic.Pointer[Dict]
 	rule PluralRule
+
+	// base and overrides are set on locales prepared by Overrides, whose
+	// entries are looked up before those of the base locale. The source
+	// is the Overrides instance that prepared the locale.
+	base      *Locale
+	overrides []*Dict
+	source    *Overrides
+	merged    atomic.Pointer[mergedDict]
+}
+
+// mergedDict keeps the dictionary prepared for a locale with overrides
+// along with the base dictionary it was prepared from.
+type mergedDict struct {
+	base *Dict
+	dict *Dict
 }
 
 const (
-	missingDictOut      = "!(MISSING: %s)"
-	localeKey      Code = "locale"
+	missingDictOut        = "!(MISSING: %s)"
+	localeKey        Code = "locale"
+	defaultLocaleKey Code = "default-locale"
 )
 
 // DefaultText when detected as an argument to a translation
@@ -33,8 +52,8 @@ func Default(txt string) DefaultText {
 func NewLocale(code Code, dict *Dict) *Locale {
 	l := &Locale{
 		code: code,
-		dict: dict,
 	}
+	l.dict.Store(dict)
 	l.rule = mapPluralRule(code)
 	return l
 
@@ -45,23 +64,122 @@ func (l *Locale) Code() Code {
 	return l.code
 }
 
+// Dict provides the dictionary containing all the locale's entries. For
+// locales with overrides applied, a dictionary with the overrides merged
+// into a copy of the locale's entries is prepared and reused until the
+// base locale's entries change.
+func (l *Locale) Dict() *Dict {
+	if l.base == nil {
+		return l.dict.Load()
+	}
+	bd := l.base.Dict()
+	if m := l.merged.Load(); m != nil && m.base == bd {
+		return m.dict
+	}
+	d := NewDict()
+	for _, od := range l.overrides {
+		d.Merge(od)
+	}
+	d.Merge(bd)
+	l.merged.Store(&mergedDict{base: bd, dict: d})
+	return d
+}
+
+// get finds the entry for the key in any overrides before the locale's
+// own dictionary.
+func (l *Locale) get(key string) *Dict {
+	return l.find(key, func(d *Dict) *Dict {
+		return d
+	})
+}
+
+// plural finds the entry for the key with the plural form for the number,
+// so that overrides may replace individual forms.
+func (l *Locale) plural(key string, n int) *Dict {
+	return l.find(key, func(d *Dict) *Dict {
+		if d == nil {
+			return nil
+		}
+		return l.rule(d, n)
+	})
+}
+
+// find looks up the key in any overrides and then in the locale's own
+// dictionary, providing the first entry accepted by the function.
+func (l *Locale) find(key string, fn func(d *Dict) *Dict) *Dict {
+	for _, od := range l.overrides {
+		if v := fn(od.Get(key)); v != nil {
+			return v
+		}
+	}
+	if l.base != nil {
+		return l.base.find(key, fn)
+	}
+	return fn(l.dict.Load().Get(key))
+}
+
+// update applies the changes made by the function to a copy of the
+// dictionary, which will then replace the current dictionary.
+func (l *Locale) update(fn func(d *Dict)) {
+	d := l.Dict().Clone()
+	if d == nil {
+		d = NewDict()
+	}
+	fn(d)
+	l.dict.Store(d)
+}
+
 // T provides the value from the dictionary stored by the locale.
 func (l *Locale) T(key string, args ...any) string {
-	return interpolate(key, l.dict.Get(key), args...)
+	return interpolate(key, l.get(key), args...)
 }
 
 // N uses the locale pluralization rules to determine which
 // string value to provide based on the provided number.
 func (l *Locale) N(key string, n int, args ...any) string {
-	d := l.dict.Get(key)
-	return interpolate(key, l.rule(d, n), args...)
+	return interpolate(key, l.plural(key, n), args...)
+}
+
+// TE provides the value from the dictionary like T, but returns an
+// error instead of a "missing" text when the key is not defined or the
+// arguments could not be inserted.
+func (l *Locale) TE(key string, args ...any) (string, error) {
+	return translate(key, l.get(key), args...)
+}
+
+// NE provides the pluralized value like N, but returns an error when the
+// key or plural form for the number is not defined, or when the arguments
+// could not be inserted.
+func (l *Locale) NE(key string, n int, args ...any) (string, error) {
+	return translatePlural(key, l.get(key), l.plural(key, n), n, args...)
+}
+
+// List provides the list of texts defined for the key, or nil if the
+// key is missing or does not contain a list.
+func (l *Locale) List(key string) []string {
+	return l.get(key).List()
+}
+
+// Int provides the whole number defined for the key, or zero if missing.
+func (l *Locale) Int(key string) int {
+	return l.get(key).Int()
+}
+
+// Float provides the number defined for the key, or zero if missing.
+func (l *Locale) Float(key string) float64 {
+	return l.get(key).Float()
+}
+
+// Bool provides the boolean defined for the key, or false if missing.
+func (l *Locale) Bool(key string) bool {
+	return l.get(key).Bool()
 }
 
 // Has performs a check to see if the key exists in the locale.
 // This is useful for checking if a key exists before attempting
 // to use it when the Default function cannot be used.
 func (l *Locale) Has(key string) bool {
-	return l.dict.Has(key)
+	return l.get(key) != nil
 }
 
 // PluralRule provides the pluralization rule for the locale.
@@ -69,15 +187,26 @@ func (l *Locale) PluralRule() PluralRule {
 	return l.rule
 }
 
+// MarshalJSON provides the JSON representation of the locale's dictionary.
+func (l *Locale) MarshalJSON() ([]byte, error) {
+	return l.Dict().MarshalJSON()
+}
+
+// MarshalYAML provides the locale's dictionary data for YAML encoders.
+func (l *Locale) MarshalYAML() (any, error) {
+	return l.Dict().MarshalYAML()
+}
+
 // UnmarshalJSON attempts to load the locale from a JSON byte slice.
 func (l *Locale) UnmarshalJSON(data []byte) error {
 	if len(data) == 0 {
 		return nil
 	}
-	l.dict = new(Dict)
-	if err := json.Unmarshal(data, l.dict); err != nil {
+	d := new(Dict)
+	if err := json.Unmarshal(data, d); err != nil {
 		return err
 	}
+	l.dict.Store(d)
 	return nil
 }
 
@@ -87,32 +216,58 @@ func (l *Locale) WithContext(ctx context.Context) context.Context {
 	return context.WithValue(ctx, localeKey, l)
 }
 
-// GetLocale retrieves the locale from the context.
+// GetLocale retrieves the locale from the context, with any overrides
+// from the context applied.
 func GetLocale(ctx context.Context) *Locale {
 	if l, ok := ctx.Value(localeKey).(*Locale); ok {
+		return GetOverrides(ctx).Apply(l)
+	}
+	return nil
+}
+
+// WithDefaultLocale adds the locale to the context to be used when a text
+// is not available in the context's own locale, like by `String.Localize`
+// or `Error.Localized`. Bundles add their default locale automatically.
+func WithDefaultLocale(ctx context.Context, l *Locale) context.Context {
+	return context.WithValue(ctx, defaultLocaleKey, l)
+}
+
+// GetDefaultLocale retrieves the default locale from the context, if any.
+func GetDefaultLocale(ctx context.Context) *Locale {
+	if l, ok := ctx.Value(defaultLocaleKey).(*Locale); ok {
 		return l
 	}
 	return nil
 }
 
 func interpolate(key string, d *Dict, args ...any) string {
+	s, ok := format(d, args...)
+	if !ok {
+		return missing(key)
+	}
+	return s
+}
+
+// format prepares the text from the dictionary's value or any default
+// in the arguments, and returns false if neither is available.
+func format(d *Dict, args ...any) (string, bool) {
 	var s string
 	s, args = extractDefault(args)
 	if d != nil {
 		s = d.value
 	}
 	if s == "" {
-		return missing(key)
+		return "", false
 	}
 	if len(args) > 0 {
 		switch arg := args[0].(type) {
 		case M:
-			return arg.Replace(s)
+			return arg.Replace(s), true
 		default:
-			return fmt.Sprintf(s, args...)
+			return fmt.Sprintf(s, args...), true
 		}
 	}
-	return s
+	return s, true
 }
 
 func extractDefault(args []any) (string, []any) {
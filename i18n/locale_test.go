@@ -8,6 +8,7 @@ import (
 	"github.com/invopop/ctxi18n/i18n"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
 )
 
 func TestLocaleGet(t *testing.T) {
@@ -32,6 +33,26 @@ func TestLocaleGet(t *testing.T) {
 	assert.Equal(t, "2 mouses", l.N("baz.random", 2, i18n.Default("%{count} mouses"), i18n.M{"count": 2}))
 }
 
+func TestLocaleTypedValues(t *testing.T) {
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+	assert.Equal(t, []string{"Sunday", "Monday", "Tuesday"}, l.List("date.day_names"))
+	assert.Nil(t, l.List("foo"))
+	assert.Equal(t, 2, l.Int("number.precision"))
+	assert.Equal(t, 0.5, l.Float("number.ratio"))
+	assert.True(t, l.Bool("number.grouping"))
+	assert.Equal(t, "2", l.T("number.precision"))
+	assert.Zero(t, l.Int("random"))
+	assert.False(t, l.Bool("random"))
+}
+
+func TestLocaleDict(t *testing.T) {
+	d := i18n.NewDict()
+	d.Add("foo", "bar")
+	l := i18n.NewLocale("en", d)
+	assert.Same(t, d, l.Dict())
+}
+
 func TestLocaleHas(t *testing.T) {
 	in := SampleLocaleData()
 	l := i18n.NewLocale("en", nil)
@@ -50,6 +71,114 @@ func TestLocaleInterpolate(t *testing.T) {
 	assert.Equal(t, "1 duck", out)
 }
 
+func TestLocaleTE(t *testing.T) {
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+
+	out, err := l.TE("foo")
+	assert.NoError(t, err)
+	assert.Equal(t, "bar", out)
+
+	out, err = l.TE("random", i18n.Default("xyz %{foo}"), i18n.M{"foo": "test"})
+	assert.NoError(t, err)
+	assert.Equal(t, "xyz test", out)
+
+	_, err = l.TE("baz.random")
+	assert.ErrorIs(t, err, i18n.ErrMissingKey)
+	assert.EqualError(t, err, "missing key: baz.random")
+
+	_, err = l.TE("baz")
+	assert.ErrorIs(t, err, i18n.ErrMissingKey)
+
+	_, err = l.TE("baz.mice.one", i18n.M{"other": 1})
+	assert.ErrorIs(t, err, i18n.ErrInterpolation)
+	assert.EqualError(t, err, "interpolation failed: baz.mice.one: no value for %{count}")
+
+	_, err = l.TE("baz.mice.one")
+	assert.ErrorIs(t, err, i18n.ErrInterpolation)
+
+	_, err = l.TE("baz.ducks.one", "one")
+	assert.ErrorIs(t, err, i18n.ErrInterpolation)
+
+	_, err = l.TE("baz.ducks.one", 1, 2)
+	assert.ErrorIs(t, err, i18n.ErrInterpolation)
+
+	t.Run("argument values", func(t *testing.T) {
+		l.Dict().Add("promo", "Promo: %s")
+		out, err := l.TE("promo", "Save 100%!")
+		assert.NoError(t, err)
+		assert.Equal(t, "Promo: Save 100%!", out)
+
+		out, err = l.TE("baz.mice.one", i18n.M{"count": "%{count}"})
+		assert.NoError(t, err)
+		assert.Equal(t, "%{count} mouse", out)
+
+		out, err = l.TE("promo", "%!d(string=x)")
+		assert.NoError(t, err)
+		assert.Equal(t, "Promo: %!d(string=x)", out)
+	})
+
+	t.Run("verbs", func(t *testing.T) {
+		l.Dict().Add("swap", "%[2]s, %[1]s (100%%)")
+		out, err := l.TE("swap", "a", "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "b, a (100%)", out)
+
+		_, err = l.TE("swap", "a")
+		assert.EqualError(t, err, "interpolation failed: swap: missing argument for %[2]s")
+
+		l.Dict().Add("width", "%*d|%-5s")
+		out, err = l.TE("width", 3, 7, "x")
+		assert.NoError(t, err)
+		assert.Equal(t, "  7|x    ", out)
+
+		_, err = l.TE("width", "3", 7, "x")
+		assert.EqualError(t, err, "interpolation failed: width: invalid argument for *: 3")
+
+		_, err = l.TE("baz.ducks.one", "one")
+		assert.EqualError(t, err, "interpolation failed: baz.ducks.one: invalid argument for %d: one")
+
+		_, err = l.TE("baz.ducks.one", 1, 2)
+		assert.EqualError(t, err, "interpolation failed: baz.ducks.one: unused arguments: [2]")
+
+		l.Dict().Add("trailing", "100%")
+		_, err = l.TE("trailing", 1)
+		assert.EqualError(t, err, "interpolation failed: trailing: missing verb")
+	})
+}
+
+func TestLocaleNE(t *testing.T) {
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+	l.Dict().Add("single", map[string]any{"one": "one item"})
+
+	out, err := l.NE("baz.mice", 2, i18n.M{"count": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "2 mice", out)
+
+	out, err = l.NE("baz.ducks", 1, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "1 duck", out)
+
+	out, err = l.NE("baz.random", 2, i18n.Default("%{count} mouses"), i18n.M{"count": 2})
+	assert.NoError(t, err)
+	assert.Equal(t, "2 mouses", out)
+
+	_, err = l.NE("random", 2)
+	assert.ErrorIs(t, err, i18n.ErrMissingKey)
+
+	out, err = l.NE("single", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "one item", out)
+
+	_, err = l.NE("single", 2)
+	assert.ErrorIs(t, err, i18n.ErrMissingPluralForm)
+	assert.EqualError(t, err, "missing plural form: single: 2")
+
+	_, err = l.NE("baz.mice", 2)
+	assert.ErrorIs(t, err, i18n.ErrInterpolation)
+}
+
 func TestLocalWithContext(t *testing.T) {
 	l := i18n.NewLocale("en", nil)
 	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
@@ -63,6 +192,18 @@ func TestLocalWithContext(t *testing.T) {
 	require.NotNil(t, l2)
 }
 
+func TestLocaleMarshalJSON(t *testing.T) {
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal([]byte(`{"foo":"bar","baz":{"qux":"quux"}}`), l))
+	out, err := json.Marshal(l)
+	require.NoError(t, err)
+	assert.Equal(t, `{"baz":{"qux":"quux"},"foo":"bar"}`, string(out))
+
+	out, err = yaml.Marshal(l)
+	require.NoError(t, err)
+	assert.Equal(t, "baz:\n    qux: quux\nfoo: bar\n", string(out))
+}
+
 func TestLocalUnmarshalJSON(t *testing.T) {
 	l := i18n.NewLocale("en", nil)
 	require.NoError(t, l.UnmarshalJSON(SampleLocaleData()))
@@ -95,6 +236,14 @@ func SampleLocaleData() []byte {
 				"one": "%d duck",
 				"other": "%d ducks"
 			}
+		},
+		"date": {
+			"day_names": ["Sunday", "Monday", "Tuesday"]
+		},
+		"number": {
+			"precision": 2,
+			"ratio": 0.5,
+			"grouping": true
 		}
 	}`)
 }
@@ -4,20 +4,140 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/fs"
+	"path"
 	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
 
 	"github.com/invopop/yaml"
 )
 
-// Locales is a map of language keys to their respective locale.
+// Locales is a map of language keys to their respective locale. Locales are
+// safe for concurrent use: new entries are prepared separately and replace
+// each locale's dictionary in one go, so lookups are never blocked while
+// files are being read.
 type Locales struct {
-	list []*Locale
+	mu     sync.RWMutex
+	list   []*Locale // available locales
+	base   []*Locale // locales loaded directly
+	layers []*layer
+}
+
+// LoadOption is used to modify the way in which files are loaded.
+type LoadOption func(*loadOptions)
+
+type loadOptions struct {
+	inferLocale bool
+	namespace   bool
+	strict      bool
+	override    bool
+}
+
+// InferLocale configures the loader to determine the locale of each file
+// from its path, instead of expecting the contents to start with a top-level
+// locale key. The file name is used if it is a locale code, as in `es.yaml`
+// or `web/es-MX.json`, otherwise the parent directory, as in
+// `es/checkout.yaml`. Only codes of known languages are recognized, and
+// paths where both the file name and directory are codes, like
+// `en/es.yaml`, will cause an error.
+func InferLocale() LoadOption {
+	return func(o *loadOptions) {
+		o.inferLocale = true
+	}
+}
+
+// NamespaceFromFile implies InferLocale, but prefers the parent directory
+// as the locale, placing the contents of files found inside it under a key
+// named after the file, so that `es/checkout.yaml` will provide keys
+// starting with `checkout.`.
+func NamespaceFromFile() LoadOption {
+	return func(o *loadOptions) {
+		o.inferLocale = true
+		o.namespace = true
+	}
+}
+
+// Strict ensures that every file is checked for duplicate keys, keys that
+// are defined as text in one place and as a map in another, and unsupported
+// values. Files with problems are skipped, and the problems are reported once
+// all the files have been read as LoadError instances containing the file
+// paths and line numbers.
+func Strict() LoadOption {
+	return func(o *loadOptions) {
+		o.strict = true
+	}
+}
+
+// Override will ensure that values from files loaded later replace those
+// already defined, instead of the default behavior of keeping the first
+// value found. Duplicate keys are not reported in strict mode when
+// overriding.
+func Override() LoadOption {
+	return func(o *loadOptions) {
+		o.override = true
+	}
 }
 
 // Load walks through all the files in the provided File System
 // and merges every one with the current list of locales.
-func (ls *Locales) Load(src fs.FS) error {
-	return fs.WalkDir(src, ".", func(path string, _ fs.DirEntry, err error) error {
+func (ls *Locales) Load(src fs.FS, opts ...LoadOption) error {
+	o := newLoadOptions(opts)
+	ds, err := readDicts(src, o)
+	if ds == nil {
+		return err
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.apply(ds, o.override)
+	ls.rebuild()
+
+	return err
+}
+
+func newLoadOptions(opts []LoadOption) *loadOptions {
+	o := new(loadOptions)
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// dicts keeps the dictionaries read from files before they are merged
+// with the locales, maintaining the order in which the codes were found.
+type dicts struct {
+	codes []Code
+	index map[Code]*Dict
+}
+
+func newDicts() *dicts {
+	return &dicts{index: make(map[Code]*Dict)}
+}
+
+func (ds *dicts) merge(code Code, d *Dict, override bool) {
+	if cur, ok := ds.index[code]; ok {
+		cur.merge(d, override)
+		return
+	}
+	if d == nil {
+		d = NewDict()
+	}
+	ds.index[code] = d
+	ds.codes = append(ds.codes, code)
+}
+
+// readDicts reads all the files from the source. Any problems found in
+// strict mode will be returned alongside the dictionaries from the files
+// that were valid.
+func readDicts(src fs.FS, o *loadOptions) (*dicts, error) {
+	var chk *checker
+	if o.strict {
+		chk = newChecker(o.override)
+	}
+	ds := newDicts()
+
+	err := fs.WalkDir(src, ".", func(path string, _ fs.DirEntry, err error) error {
 		if err != nil {
 			return fmt.Errorf("walking directory: %w", err)
 		}
@@ -34,50 +154,133 @@ func (ls *Locales) Load(src fs.FS) error {
 			return fmt.Errorf("reading file '%s': %w", path, err)
 		}
 
-		if err := yaml.Unmarshal(data, ls); err != nil {
-			return fmt.Errorf("unmarshalling file '%s': %w", path, err)
+		return ds.readFile(path, data, o, chk)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ds, chk.err()
+}
+
+func (ds *dicts) readFile(path string, data []byte, o *loadOptions, chk *checker) error {
+	var code Code
+	var ns string
+	if o.inferLocale {
+		var err error
+		if code, ns, err = inferLocale(path, o.namespace); err != nil {
+			return err
 		}
+	}
+
+	if chk != nil {
+		prefix := code.String()
+		if ns != "" {
+			prefix = prefix + "." + ns
+		}
+		ok, err := chk.check(path, data, prefix)
+		if err != nil {
+			return fmt.Errorf("checking file '%s': %w", path, err)
+		}
+		if !ok {
+			return nil // problems reported later
+		}
+	}
 
+	if o.inferLocale {
+		d := NewDict()
+		if err := yaml.Unmarshal(data, d); err != nil {
+			return fmt.Errorf("unmarshalling file '%s': %w", path, err)
+		}
+		if ns != "" {
+			nd := NewDict()
+			nd.Add(ns, d)
+			d = nd
+		}
+		ds.merge(code, d, o.override)
 		return nil
-	})
+	}
+
+	aux := make(map[Code]*Dict)
+	if err := yaml.Unmarshal(data, &aux); err != nil {
+		return fmt.Errorf("unmarshalling file '%s': %w", path, err)
+	}
+	for _, c := range sortedCodes(aux) {
+		ds.merge(c, aux[c], o.override)
+	}
+
+	return nil
+}
+
+// inferLocale determines the locale code and optional namespace to use
+// for the file in the provided path.
+func inferLocale(name string, namespace bool) (Code, string, error) {
+	stem := strings.TrimSuffix(path.Base(name), path.Ext(name))
+	dir := path.Base(path.Dir(name))
+	stemCode := isLanguageCode(stem)
+	dirCode := isLanguageCode(dir)
+	switch {
+	case namespace && dirCode:
+		if stem == dir {
+			return Code(dir), "", nil
+		}
+		return Code(dir), stem, nil
+	case stemCode && dirCode && stem != dir:
+		return "", "", fmt.Errorf("ambiguous locale in path '%s': %s or %s", name, dir, stem)
+	case stemCode:
+		return Code(stem), "", nil
+	case dirCode:
+		return Code(dir), "", nil
+	}
+	return "", "", fmt.Errorf("unable to determine locale from path '%s'", name)
 }
 
 // LoadWithDefault performs the regular load operation, but follows up with
 // a second operation that will ensure that default dictionary is merged with
 // every other locale, thus ensuring that every text will have a fallback.
-func (ls *Locales) LoadWithDefault(src fs.FS, locale Code) error {
-	if err := ls.Load(src); err != nil {
+// As with Load, any problems found in strict mode are returned after the
+// valid files have been applied.
+func (ls *Locales) LoadWithDefault(src fs.FS, locale Code, opts ...LoadOption) error {
+	o := newLoadOptions(opts)
+	ds, err := readDicts(src, o)
+	if ds == nil {
 		return err
 	}
 
-	l := ls.Get(locale)
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.apply(ds, o.override)
+	defer ls.rebuild()
+
+	l := findLocale(ls.base, locale)
 	if l == nil {
 		return fmt.Errorf("undefined default locale: %s", locale)
 	}
-	for _, loc := range ls.list {
+	for _, loc := range ls.base {
 		if loc == l {
 			continue
 		}
-		loc.dict.Merge(l.dict)
+		loc.update(func(d *Dict) {
+			d.Merge(l.Dict())
+		})
 	}
 
-	return nil
+	return err
 }
 
 // Get provides the define Locale object for the matching key.
 func (ls *Locales) Get(code Code) *Locale {
-	for _, loc := range ls.list {
-		if loc.Code() == code {
-			return loc
-		}
-	}
-	return nil
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	return findLocale(ls.list, code)
 }
 
 // Match attempts to find the best possible matching locale based on the
 // locale string provided. The locale string is parsed according to the
 // "Accept-Language" header format defined in RFC9110.
 func (ls *Locales) Match(locale string) *Locale {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
 	codes := ParseAcceptLanguage(locale)
 	for _, code := range codes {
 		for _, loc := range ls.list {
@@ -92,6 +295,8 @@ func (ls *Locales) Match(locale string) *Locale {
 // Codes provides a list of locale codes defined in the
 // list.
 func (ls *Locales) Codes() []Code {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
 	codes := make([]Code, len(ls.list))
 	for i, l := range ls.list {
 		codes[i] = l.Code()
@@ -99,6 +304,87 @@ func (ls *Locales) Codes() []Code {
 	return codes
 }
 
+// Add includes the provided locale in the list, or returns an error if
+// a locale with the same code is already defined, either directly or by
+// one of the layers.
+func (ls *Locales) Add(l *Locale) error {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	if findLocale(ls.list, l.code) != nil {
+		return fmt.Errorf("locale already defined: %s", l.code)
+	}
+	ls.base = append(ls.base[:len(ls.base):len(ls.base)], l)
+	ls.rebuild()
+	return nil
+}
+
+// Replace swaps the locale with the same code as the one provided, or
+// adds it to the list if not already defined.
+func (ls *Locales) Replace(l *Locale) {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	list := make([]*Locale, 0, len(ls.base)+1)
+	found := false
+	for _, loc := range ls.base {
+		if loc.code == l.code {
+			loc = l
+			found = true
+		}
+		list = append(list, loc)
+	}
+	if !found {
+		list = append(list, l)
+	}
+	ls.base = list
+	ls.rebuild()
+}
+
+// Remove deletes the locale with the matching code from the list, and
+// returns false if it was not defined. Only locales added or loaded
+// directly are removed, so a locale provided by any of the layers will
+// remain available until the layers are replaced.
+func (ls *Locales) Remove(code Code) bool {
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	list := make([]*Locale, 0, len(ls.base))
+	for _, loc := range ls.base {
+		if loc.code != code {
+			list = append(list, loc)
+		}
+	}
+	if len(list) == len(ls.base) {
+		return false
+	}
+	ls.base = list
+	ls.rebuild()
+	return true
+}
+
+// MarshalJSON provides the JSON representation of all the locales using
+// the same structure expected by Load, with the locale codes as the
+// top-level keys.
+func (ls *Locales) MarshalJSON() ([]byte, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	aux := make(map[Code]*Dict, len(ls.list))
+	for _, l := range ls.list {
+		aux[l.code] = l.Dict()
+	}
+	return json.Marshal(aux)
+}
+
+// MarshalYAML provides the data of all the locales for YAML encoders, using
+// the same structure expected by Load.
+func (ls *Locales) MarshalYAML() (any, error) {
+	ls.mu.RLock()
+	defer ls.mu.RUnlock()
+	aux := make(map[string]any, len(ls.list))
+	for _, l := range ls.list {
+		aux[l.code.String()] = l.Dict().export()
+	}
+	return aux, nil
+}
+
 // UnmarshalJSON attempts to load the locales from a JSON byte slice
 // and merge them into any existing locales.
 func (ls *Locales) UnmarshalJSON(data []byte) error {
@@ -109,12 +395,50 @@ func (ls *Locales) UnmarshalJSON(data []byte) error {
 	if err := json.Unmarshal(data, &aux); err != nil {
 		return err
 	}
-	for c, v := range aux {
-		if l := ls.Get(c); l != nil {
-			l.dict.Merge(v)
-		} else {
-			ls.list = append(ls.list, NewLocale(c, v))
+	ds := newDicts()
+	for _, c := range sortedCodes(aux) {
+		ds.merge(c, aux[c], false)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.apply(ds, false)
+	ls.rebuild()
+	return nil
+}
+
+// apply merges the dictionaries with the directly loaded locales, creating
+// new locales if needed. Existing values will only be replaced when
+// overriding. The caller must hold the write lock.
+func (ls *Locales) apply(ds *dicts, override bool) {
+	for _, c := range ds.codes {
+		d := ds.index[c]
+		if l := findLocale(ls.base, c); l != nil {
+			l.update(func(nd *Dict) {
+				nd.merge(d, override)
+			})
+			continue
+		}
+		ls.base = append(ls.base[:len(ls.base):len(ls.base)], NewLocale(c, d))
+	}
+}
+
+func findLocale(list []*Locale, code Code) *Locale {
+	for _, loc := range list {
+		if loc.Code() == code {
+			return loc
 		}
 	}
 	return nil
 }
+
+func sortedCodes(m map[Code]*Dict) []Code {
+	codes := make([]Code, 0, len(m))
+	for c := range m {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	return codes
+}
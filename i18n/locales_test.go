@@ -2,12 +2,16 @@ package i18n_test
 
 import (
 	"encoding/json"
+	"sync"
 	"testing"
+	"testing/fstest"
 
 	"github.com/invopop/ctxi18n/i18n"
 	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/invopop/yaml"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	yamlv3 "gopkg.in/yaml.v3"
 )
 
 func TestLocalesLoad(t *testing.T) {
@@ -20,6 +24,9 @@ func TestLocalesLoad(t *testing.T) {
 	require.NotNil(t, en)
 	assert.Equal(t, "Log In", en.T("login.button"))
 	assert.Equal(t, "Extensions", en.T("ext.test"))
+	assert.Len(t, en.List("date.day_names"), 7)
+	assert.Equal(t, "Monday", en.List("date.day_names")[1])
+	assert.Equal(t, 2, en.Int("number.precision"))
 
 	es := ls.Get("es")
 	require.NotNil(t, es)
@@ -40,6 +47,83 @@ func TestLocalesLoad(t *testing.T) {
 	assert.Nil(t, ls.Match("inv"))
 }
 
+func TestLocalesLoadInferLocale(t *testing.T) {
+	src := fstest.MapFS{
+		"es.yaml":          {Data: []byte("welcome: \"Bienvenido\"")},
+		"es-MX.json":       {Data: []byte(`{"welcome": "Bienvenido, amigo"}`)},
+		"locales/en.yml":   {Data: []byte("welcome: \"Welcome\"")},
+		"es/checkout.yaml": {Data: []byte("pay: \"Pagar\"")},
+	}
+
+	t.Run("from path", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(src, i18n.InferLocale()))
+		assert.ElementsMatch(t, []i18n.Code{"es", "es-MX", "en"}, ls.Codes())
+
+		es := ls.Get("es")
+		require.NotNil(t, es)
+		assert.Equal(t, "Bienvenido", es.T("welcome"))
+		assert.Equal(t, "Pagar", es.T("pay"))
+		assert.Equal(t, "Bienvenido, amigo", ls.Get("es-MX").T("welcome"))
+		assert.Equal(t, "Welcome", ls.Get("en").T("welcome"))
+	})
+
+	t.Run("with namespace", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(src, i18n.NamespaceFromFile()))
+		es := ls.Get("es")
+		require.NotNil(t, es)
+		assert.Equal(t, "Bienvenido", es.T("welcome"))
+		assert.Equal(t, "Pagar", es.T("checkout.pay"))
+		assert.False(t, es.Has("pay"))
+		assert.Equal(t, "Welcome", ls.Get("en").T("welcome"))
+	})
+
+	t.Run("unknown locale", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		err := ls.Load(fstest.MapFS{
+			"common/messages.yaml": {Data: []byte("welcome: \"Welcome\"")},
+		}, i18n.InferLocale())
+		assert.ErrorContains(t, err, "unable to determine locale from path 'common/messages.yaml'")
+	})
+
+	t.Run("file name before directory", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(fstest.MapFS{
+			"web/es.yaml":  {Data: []byte("welcome: \"Bienvenido\"")},
+			"app/en.json":  {Data: []byte(`{"welcome": "Welcome"}`)},
+			"fr/shop.yaml": {Data: []byte("pay: \"Payer\"")},
+		}, i18n.InferLocale()))
+		assert.ElementsMatch(t, []i18n.Code{"es", "en", "fr"}, ls.Codes())
+		assert.Equal(t, "Bienvenido", ls.Get("es").T("welcome"))
+		assert.Equal(t, "Welcome", ls.Get("en").T("welcome"))
+		assert.Equal(t, "Payer", ls.Get("fr").T("pay"))
+	})
+
+	t.Run("unknown language", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		err := ls.Load(fstest.MapFS{
+			"help/faq.yaml": {Data: []byte("question: \"Why?\"")},
+		}, i18n.InferLocale())
+		assert.ErrorContains(t, err, "unable to determine locale from path 'help/faq.yaml'")
+		assert.Empty(t, ls.Codes())
+	})
+
+	t.Run("ambiguous", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		err := ls.Load(fstest.MapFS{
+			"en/es.yaml": {Data: []byte("welcome: \"Bienvenido\"")},
+		}, i18n.InferLocale())
+		assert.ErrorContains(t, err, "ambiguous locale in path 'en/es.yaml': en or es")
+
+		ls = new(i18n.Locales)
+		require.NoError(t, ls.Load(fstest.MapFS{
+			"en/es.yaml": {Data: []byte("welcome: \"Welcome\"")},
+		}, i18n.NamespaceFromFile()))
+		assert.Equal(t, "Welcome", ls.Get("en").T("es.welcome"))
+	})
+}
+
 func TestLoadWithDefault(t *testing.T) {
 	ls := new(i18n.Locales)
 	err := ls.LoadWithDefault(examples.Content, "en")
@@ -63,6 +147,39 @@ func TestLoadWithDefault(t *testing.T) {
 
 }
 
+func TestLoadWithDefaultIsolation(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.LoadWithDefault(examples.Content, "en"))
+	en := ls.Get("en")
+	es := ls.Get("es")
+	require.NotNil(t, es)
+
+	// "date" is only defined in the default locale
+	es.Dict().Get("date").Add("month_names", []string{"enero"})
+	en.Dict().Get("login").Add("extra", "Extra")
+	assert.False(t, en.Has("date.month_names"))
+	assert.True(t, es.Has("date.month_names"))
+	assert.False(t, es.Has("login.extra"))
+
+	t.Run("reload", func(t *testing.T) {
+		require.NoError(t, ls.LoadWithDefault(examples.Content, "en"))
+		assert.False(t, en.Has("date.month_names"))
+
+		es.Dict().Get("number").Add("grouping", true)
+		assert.False(t, en.Has("number.grouping"))
+	})
+
+	t.Run("reloader", func(t *testing.T) {
+		r, err := i18n.NewReloader(examples.Content, i18n.ReloadWithDefault("en"))
+		require.NoError(t, err)
+		es := r.Locales().Get("es")
+		es.Dict().Get("date").Add("month_names", []string{"enero"})
+		require.NoError(t, r.Reload())
+		assert.False(t, r.Locales().Get("es").Has("date.month_names"))
+		assert.False(t, r.Locales().Get("en").Has("date.month_names"))
+	})
+}
+
 func TestLocalesUnmarshalJSON(t *testing.T) {
 	in := SampleLocales()
 	ls := new(i18n.Locales)
@@ -100,6 +217,49 @@ func TestLocalesUnmarshalJSON(t *testing.T) {
 	})
 }
 
+func TestLocalesMarshal(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.LoadWithDefault(examples.Content, "en"))
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(ls)
+		require.NoError(t, err)
+		again, err := json.Marshal(ls)
+		require.NoError(t, err)
+		assert.Equal(t, data, again, "deterministic")
+
+		ls2 := new(i18n.Locales)
+		require.NoError(t, ls2.Load(fstest.MapFS{"all.json": {Data: data}}))
+		out, err := json.Marshal(ls2)
+		require.NoError(t, err)
+		assert.JSONEq(t, string(data), string(out))
+		assert.Equal(t, "Special Label", ls2.Get("es").T("special_label"))
+		assert.Equal(t, 2, ls2.Get("en").Int("number.precision"))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := yaml.Marshal(ls)
+		require.NoError(t, err)
+		assert.Contains(t, string(data), "en:\n    about_us: About Us\n")
+
+		ls2 := new(i18n.Locales)
+		require.NoError(t, ls2.Load(fstest.MapFS{"all.yaml": {Data: data}}))
+		out, err := yaml.Marshal(ls2)
+		require.NoError(t, err)
+		assert.Equal(t, string(data), string(out))
+		assert.Equal(t, "Monday", ls2.Get("en").List("date.day_names")[1])
+	})
+
+	t.Run("yaml v3", func(t *testing.T) {
+		data, err := yamlv3.Marshal(ls)
+		require.NoError(t, err)
+		ls2 := new(i18n.Locales)
+		require.NoError(t, ls2.Load(fstest.MapFS{"all.yaml": {Data: data}}))
+		assert.Equal(t, "Iniciar Sesión", ls2.Get("es").T("login.button"))
+		assert.Equal(t, 2, ls2.Get("en").Int("number.precision"))
+	})
+}
+
 func TestLocalesCodes(t *testing.T) {
 	in := SampleLocales()
 	ls := new(i18n.Locales)
@@ -110,6 +270,95 @@ func TestLocalesCodes(t *testing.T) {
 	assert.Contains(t, codes, i18n.Code("es"))
 }
 
+func TestLocalesAddRemoveReplace(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, json.Unmarshal(SampleLocales(), ls))
+
+	fr := i18n.NewLocale("fr", i18n.NewDict())
+	fr.Dict().Add("foo", "barre")
+	require.NoError(t, ls.Add(fr))
+	assert.Equal(t, "barre", ls.Get("fr").T("foo"))
+	assert.ErrorContains(t, ls.Add(fr), "locale already defined: fr")
+
+	en := i18n.NewLocale("en", i18n.NewDict())
+	en.Dict().Add("foo", "new")
+	ls.Replace(en)
+	assert.Equal(t, "new", ls.Get("en").T("foo"))
+	assert.Len(t, ls.Codes(), 3)
+
+	assert.True(t, ls.Remove("es"))
+	assert.False(t, ls.Remove("es"))
+	assert.Nil(t, ls.Get("es"))
+	assert.Equal(t, []i18n.Code{"en", "fr"}, ls.Codes())
+
+	de := i18n.NewLocale("de", i18n.NewDict())
+	ls.Replace(de)
+	assert.Equal(t, de, ls.Get("de"))
+
+	t.Run("with layers", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(fstest.MapFS{
+			"en.yaml": {Data: []byte("en:\n  foo: \"bar\"\n")},
+		}))
+		require.NoError(t, ls.LoadLayers(i18n.Layer{
+			Name: "layer",
+			Src: fstest.MapFS{
+				"en.yaml": {Data: []byte("en:\n  baz: \"qux\"\n")},
+				"fr.yaml": {Data: []byte("fr:\n  foo: \"barre\"\n")},
+			},
+		}))
+
+		err := ls.Add(i18n.NewLocale("fr", i18n.NewDict()))
+		assert.ErrorContains(t, err, "locale already defined: fr")
+		assert.Equal(t, "barre", ls.Get("fr").T("foo"))
+
+		assert.True(t, ls.Remove("en"))
+		en := ls.Get("en")
+		require.NotNil(t, en, "still provided by layer")
+		assert.Equal(t, "qux", en.T("baz"))
+		assert.False(t, en.Has("foo"))
+		assert.False(t, ls.Remove("fr"), "only defined by layer")
+		assert.NotNil(t, ls.Get("fr"))
+	})
+}
+
+func TestLocalesConcurrency(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.Load(examples.Content))
+	en := ls.Get("en")
+	src := fstest.MapFS{
+		"en.yaml": {Data: []byte("en:\n  login:\n    button: \"Enter\"\n")},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				assert.NoError(t, ls.Load(src, i18n.Override()))
+				ls.Replace(i18n.NewLocale("fr", nil))
+				ls.Remove("fr")
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l := ls.Match("en-US,en;q=0.8")
+				assert.NotNil(t, l)
+				assert.NotEmpty(t, l.T("login.button"))
+				assert.NotEmpty(t, ls.Codes())
+				_, err := json.Marshal(ls)
+				assert.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, "Enter", en.T("login.button"), "existing locale updated")
+	assert.Equal(t, "Sign Up", en.T("login.signup-button"))
+}
+
 func SampleLocales() []byte {
 	return []byte(`{
 		"en": {
@@ -0,0 +1,63 @@
+package i18n
+
+import "context"
+
+// Message contains everything needed to translate a text at a later time,
+// for example when the locale is only known once a notification prepared
+// in a background job is delivered. Messages may be serialized to JSON.
+type Message struct {
+	// Key of the translation.
+	Key string `json:"key"`
+	// Count is used to pluralize the translation, if set.
+	Count *int `json:"count,omitempty"`
+	// Args used to interpolate the translation, if any.
+	Args M `json:"args,omitempty"`
+	// Default text to use if the translation is missing.
+	Default string `json:"default,omitempty"`
+}
+
+// NewMessage prepares a message for the key with optional arguments.
+func NewMessage(key string, args M) Message {
+	return Message{Key: key, Args: args}
+}
+
+// NewPluralMessage prepares a message for the key that will be pluralized
+// using the count.
+func NewPluralMessage(key string, count int, args M) Message {
+	return Message{Key: key, Count: &count, Args: args}
+}
+
+// WithDefault provides a copy of the message with the default text.
+func (m Message) WithDefault(txt string) Message {
+	m.Default = txt
+	return m
+}
+
+func (m Message) args() []any {
+	args := make([]any, 0, 2)
+	if m.Args != nil {
+		args = append(args, m.Args)
+	}
+	if m.Default != "" {
+		args = append(args, Default(m.Default))
+	}
+	return args
+}
+
+// Render translates the message in the same way as T, or N if the message
+// has a count.
+func (l *Locale) Render(m Message) string {
+	if m.Count != nil {
+		return l.N(m.Key, *m.Count, m.args()...)
+	}
+	return l.T(m.Key, m.args()...)
+}
+
+// Render translates the message using the locale in the context in the
+// same way as T, or N if the message has a count.
+func Render(ctx context.Context, m Message) string {
+	if m.Count != nil {
+		return N(ctx, m.Key, *m.Count, m.args()...)
+	}
+	return T(ctx, m.Key, m.args()...)
+}
@@ -0,0 +1,59 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessage(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, json.Unmarshal(SampleLocales(), ls))
+	require.NoError(t, ls.UnmarshalJSON([]byte(`{"en":{"hello":"Hello, %{name}"},"es":{"hello":"Hola, %{name}"}}`)))
+	en := ls.Get("en")
+	es := ls.Get("es")
+
+	hello := i18n.NewMessage("hello", i18n.M{"name": "Sam"})
+	mice := i18n.NewPluralMessage("baz.plural", 2, i18n.M{"count": 2})
+	missing := i18n.NewMessage("bad", nil).WithDefault("Default")
+
+	assert.Equal(t, "Hello, Sam", en.Render(hello))
+	assert.Equal(t, "Hola, Sam", es.Render(hello))
+	assert.Equal(t, "2 mice", en.Render(mice))
+	assert.Equal(t, "Default", en.Render(missing))
+	assert.Equal(t, "!(MISSING: bad)", en.Render(i18n.NewMessage("bad", nil)))
+	assert.Equal(t, "quux", en.Render(i18n.NewMessage("baz.qux", nil)))
+
+	ctx := i18n.WithScope(es.WithContext(context.Background()), "baz")
+	assert.Equal(t, "2 ratones", i18n.Render(ctx, i18n.NewPluralMessage(".plural", 2, i18n.M{"count": 2})))
+	assert.Equal(t, "quuxa", i18n.Render(ctx, i18n.NewMessage(".qux", nil)))
+	assert.Equal(t, "!(MISSING LOCALE)", i18n.Render(context.Background(), hello))
+
+	var calls []string
+	ctx = i18n.WithMissingKeyHandler(ctx, func(_ context.Context, _ i18n.Code, key string, _ ...any) (string, bool) {
+		calls = append(calls, key)
+		return "", false
+	})
+	i18n.Render(ctx, i18n.NewMessage("bad", nil))
+	assert.Equal(t, []string{"bad"}, calls, "same lookup path as T")
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal([]i18n.Message{hello, mice, missing})
+		require.NoError(t, err)
+		assert.JSONEq(t, `[
+			{"key":"hello","args":{"name":"Sam"}},
+			{"key":"baz.plural","count":2,"args":{"count":2}},
+			{"key":"bad","default":"Default"}
+		]`, string(data))
+
+		var out []i18n.Message
+		require.NoError(t, json.Unmarshal(data, &out))
+		assert.Equal(t, "Hola, Sam", es.Render(out[0]))
+		assert.Equal(t, "2 ratones", es.Render(out[1]))
+		assert.Equal(t, "Default", es.Render(out[2]))
+	})
+}
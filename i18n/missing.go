@@ -0,0 +1,85 @@
+package i18n
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+type missingType string
+
+const (
+	missingKeyHandlerKey missingType = "missing-key-handler"
+)
+
+// MissingKeyHandler is called by the context based translation methods
+// whenever a key could not be found, or when there is no locale in the
+// context, in which case the code will be empty. Handlers may be used to
+// log or count missing keys, and may provide a replacement text by
+// returning true. Handlers are also called for missing keys that have a
+// default text, but the default will be used instead of any replacement.
+type MissingKeyHandler func(ctx context.Context, code Code, key string, args ...any) (string, bool)
+
+// WithMissingKeyHandler adds the handler to the context so that it will
+// be called by `T` and `N` whenever a translation is missing.
+func WithMissingKeyHandler(ctx context.Context, h MissingKeyHandler) context.Context {
+	return context.WithValue(ctx, missingKeyHandlerKey, h)
+}
+
+// GetMissingKeyHandler provides the handler stored in the context, if any.
+func GetMissingKeyHandler(ctx context.Context) MissingKeyHandler {
+	if h, ok := ctx.Value(missingKeyHandlerKey).(MissingKeyHandler); ok {
+		return h
+	}
+	return nil
+}
+
+// handleMissing calls the handler in the context, if any, and provides
+// either its replacement or the default output.
+func handleMissing(ctx context.Context, code Code, key string, out string, args []any) string {
+	h := GetMissingKeyHandler(ctx)
+	if h == nil {
+		return out
+	}
+	if s, ok := h(ctx, code, key, args...); ok {
+		return s
+	}
+	return out
+}
+
+// LogMissingKeys provides a handler that will log each missing key with
+// the provided logger, or the default logger if nil, at most once in the
+// interval for each locale and key so that busy applications do not flood
+// their logs. Keys not seen again within the interval are forgotten, so
+// that memory does not grow with every distinct key ever reported.
+func LogMissingKeys(logger *slog.Logger, interval time.Duration) MissingKeyHandler {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	th := newThrottle(interval)
+	return func(ctx context.Context, code Code, key string, _ ...any) (string, bool) {
+		if !th.allow(code, key, time.Now()) {
+			return "", false
+		}
+		logger.WarnContext(ctx, "missing translation",
+			slog.String("locale", code.String()),
+			slog.String("key", key),
+		)
+		return "", false
+	}
+}
+
+// ChainMissingKeyHandlers combines multiple handlers into one that will
+// call each of them in order, providing the first replacement text.
+func ChainMissingKeyHandlers(handlers ...MissingKeyHandler) MissingKeyHandler {
+	return func(ctx context.Context, code Code, key string, args ...any) (string, bool) {
+		var out string
+		var found bool
+		for _, h := range handlers {
+			if s, ok := h(ctx, code, key, args...); ok && !found {
+				out, found = s, true
+			}
+		}
+		return out, found
+	}
+}
@@ -0,0 +1,90 @@
+package i18n_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMissingKeyHandler(t *testing.T) {
+	d := i18n.NewDict()
+	d.Add("key", "value")
+	d.Add("plural", map[string]any{"other": "%{count} items"})
+	l := i18n.NewLocale("en", d)
+
+	type call struct {
+		code i18n.Code
+		key  string
+		args []any
+	}
+	var calls []call
+	h := func(_ context.Context, code i18n.Code, key string, args ...any) (string, bool) {
+		calls = append(calls, call{code, key, args})
+		if key == "replaced" {
+			return "replacement", true
+		}
+		return "", false
+	}
+	ctx := i18n.WithMissingKeyHandler(context.Background(), h)
+	assert.NotNil(t, i18n.GetMissingKeyHandler(ctx))
+	assert.Equal(t, "!(MISSING LOCALE)", i18n.T(ctx, "key"))
+	assert.Equal(t, "!(MISSING LOCALE)", i18n.N(ctx, "key", 1))
+
+	ctx = l.WithContext(ctx)
+	assert.Equal(t, "value", i18n.T(ctx, "key"))
+	assert.Equal(t, "2 items", i18n.N(ctx, "plural", 2, i18n.M{"count": 2}))
+	assert.Equal(t, "fallback", i18n.T(ctx, "bad", i18n.Default("fallback")))
+	assert.Equal(t, "!(MISSING: bad)", i18n.T(ctx, "bad", i18n.M{"a": 1}))
+	assert.Equal(t, "!(MISSING: bad.count)", i18n.N(ctx, "bad.count", 3))
+	assert.Equal(t, "replacement", i18n.T(ctx, "replaced"))
+
+	sctx := i18n.WithScope(ctx, "scope")
+	assert.Equal(t, "!(MISSING: scope.foo)", i18n.T(sctx, ".foo"))
+
+	assert.Equal(t, []call{
+		{"", "key", nil},
+		{"", "key", nil},
+		{"en", "bad", []any{i18n.Default("fallback")}},
+		{"en", "bad", []any{i18n.M{"a": 1}}},
+		{"en", "bad.count", nil},
+		{"en", "replaced", nil},
+		{"en", "scope.foo", nil},
+	}, calls)
+}
+
+func TestLogMissingKeys(t *testing.T) {
+	buf := new(bytes.Buffer)
+	logger := slog.New(slog.NewTextHandler(buf, nil))
+	l := i18n.NewLocale("en", i18n.NewDict())
+	ctx := i18n.WithMissingKeyHandler(context.Background(), i18n.LogMissingKeys(logger, time.Hour))
+	ctx = l.WithContext(ctx)
+
+	assert.Equal(t, "!(MISSING: foo)", i18n.T(ctx, "foo"))
+	i18n.T(ctx, "foo")
+	i18n.T(ctx, "bar")
+	out := buf.String()
+	assert.Equal(t, 2, strings.Count(out, "missing translation"))
+	assert.Contains(t, out, "locale=en key=foo")
+	assert.Contains(t, out, "locale=en key=bar")
+
+	buf.Reset()
+	ctx = i18n.WithMissingKeyHandler(ctx, i18n.LogMissingKeys(logger, 0))
+	i18n.T(ctx, "foo")
+	i18n.T(ctx, "foo")
+	assert.Equal(t, 2, strings.Count(buf.String(), "missing translation"))
+
+	buf.Reset()
+	ctx = i18n.WithMissingKeyHandler(ctx, i18n.LogMissingKeys(logger, 20*time.Millisecond))
+	i18n.T(ctx, "foo")
+	i18n.T(ctx, "bar")
+	time.Sleep(30 * time.Millisecond)
+	i18n.T(ctx, "foo")
+	i18n.T(ctx, "foo")
+	assert.Equal(t, 3, strings.Count(buf.String(), "missing translation"), "logged again after interval")
+}
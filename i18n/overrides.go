@@ -0,0 +1,104 @@
+package i18n
+
+import (
+	"context"
+	"sync"
+)
+
+type overridesType string
+
+const (
+	overridesKey overridesType = "overrides"
+)
+
+// Overrides contains entries that replace those defined in the locales,
+// for example to allow each tenant in an application to rename concepts.
+// Entries are looked up in the overrides before the locale's own
+// dictionary, so the locale's entries are never copied.
+type Overrides struct {
+	mu      sync.RWMutex
+	all     *Dict
+	locales map[Code]*Dict
+	cache   map[Code]*Locale
+}
+
+// NewOverrides prepares a new set of overrides whose entries will be used
+// with every locale. The dictionary may be nil if only locale specific
+// overrides are needed.
+func NewOverrides(d *Dict) *Overrides {
+	return &Overrides{
+		all:     d,
+		locales: make(map[Code]*Dict),
+		cache:   make(map[Code]*Locale),
+	}
+}
+
+// Add includes overrides for a specific locale, which take precedence over
+// the overrides used with every locale. Adding entries for the same locale
+// again will merge them, keeping the newest values.
+func (o *Overrides) Add(code Code, d *Dict) *Overrides {
+	if d == nil {
+		return o
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if cur, ok := o.locales[code]; ok {
+		nd := d.Clone()
+		nd.Merge(cur)
+		d = nd
+	}
+	o.locales[code] = d
+	o.cache = make(map[Code]*Locale)
+	return o
+}
+
+// Apply provides a locale with the same code and plural rules as the one
+// provided, but with the overrides replacing its entries. The locale is
+// returned as is if there are no overrides for it. Locales already
+// prepared by the same overrides are applied from their base again.
+func (o *Overrides) Apply(l *Locale) *Locale {
+	if o == nil || l == nil {
+		return l
+	}
+	if l.source == o {
+		l = l.base
+	}
+
+	o.mu.RLock()
+	ol, ok := o.cache[l.code]
+	o.mu.RUnlock()
+	if ok && ol.base == l {
+		return ol
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	ol = &Locale{code: l.code, rule: l.rule, base: l, source: o}
+	if d := o.locales[l.code]; d != nil {
+		ol.overrides = append(ol.overrides, d)
+	}
+	if o.all != nil {
+		ol.overrides = append(ol.overrides, o.all)
+	}
+	if len(ol.overrides) == 0 {
+		return l
+	}
+	o.cache[l.code] = ol
+	return ol
+}
+
+// WithOverrides adds the overrides to the context so that they will be
+// used instead of the entries from the locale returned by `GetLocale`,
+// and thus by `T`, `N`, `Has` and all similar methods. Any overrides
+// already in the context will be replaced.
+func WithOverrides(ctx context.Context, o *Overrides) context.Context {
+	return context.WithValue(ctx, overridesKey, o)
+}
+
+// GetOverrides provides the overrides stored in the context, if any.
+func GetOverrides(ctx context.Context) *Overrides {
+	if o, ok := ctx.Value(overridesKey).(*Overrides); ok {
+		return o
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverrides(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, json.Unmarshal(SampleLocales(), ls))
+	en := ls.Get("en")
+	es := ls.Get("es")
+
+	all := i18n.NewDict()
+	all.Add("foo", "shared")
+	esd := i18n.NewDict()
+	require.NoError(t, json.Unmarshal([]byte(`{"foo":"tenant","baz":{"plural":{"one":"%{count} ratoncito"}}}`), esd))
+	o := i18n.NewOverrides(all).Add("es", esd)
+
+	ctx := i18n.WithOverrides(en.WithContext(context.Background()), o)
+	assert.Equal(t, "shared", i18n.T(ctx, "foo"))
+	assert.Equal(t, "quux", i18n.T(ctx, "baz.qux"))
+	assert.Equal(t, "bar", en.T("foo"), "locale unchanged")
+
+	ctx = i18n.WithOverrides(es.WithContext(context.Background()), o)
+	assert.Equal(t, "tenant", i18n.T(ctx, "foo"))
+	assert.Equal(t, "1 ratoncito", i18n.N(ctx, "baz.plural", 1, i18n.M{"count": 1}))
+	assert.Equal(t, "2 ratones", i18n.N(ctx, "baz.plural", 2, i18n.M{"count": 2}))
+	assert.True(t, i18n.Has(ctx, "baz.qux"))
+	assert.Equal(t, i18n.Code("es"), i18n.GetLocale(ctx).Code())
+
+	t.Run("cached", func(t *testing.T) {
+		assert.Same(t, o.Apply(es), o.Apply(es))
+		assert.Same(t, es, i18n.NewOverrides(nil).Apply(es))
+		assert.Same(t, en, i18n.GetOverrides(context.Background()).Apply(en))
+	})
+
+	t.Run("already applied", func(t *testing.T) {
+		ol := o.Apply(es)
+		assert.Same(t, ol, o.Apply(ol))
+		assert.Same(t, ol, o.Apply(es), "cache kept")
+
+		other := i18n.NewOverrides(nil).Add("es", i18n.NewDict())
+		assert.NotSame(t, ol, other.Apply(ol))
+		assert.Equal(t, "tenant", other.Apply(ol).T("foo"))
+	})
+
+	t.Run("locale reload", func(t *testing.T) {
+		prev := o.Apply(es)
+		require.NoError(t, json.Unmarshal([]byte(`{"es":{"extra":"nuevo"}}`), ls))
+		next := o.Apply(es)
+		assert.Same(t, prev, next, "no copy needed")
+		assert.Equal(t, "nuevo", next.T("extra"))
+		assert.Equal(t, "tenant", next.T("foo"))
+	})
+
+	t.Run("dict", func(t *testing.T) {
+		d := o.Apply(es).Dict()
+		assert.Equal(t, "tenant", d.Get("foo").Value())
+		assert.Equal(t, "quuxa", d.Get("baz.qux").Value())
+		assert.Equal(t, "bara", es.Dict().Get("foo").Value(), "locale unchanged")
+		assert.Same(t, d, o.Apply(es).Dict(), "reused")
+
+		require.NoError(t, json.Unmarshal([]byte(`{"es":{"other":"otro"}}`), ls))
+		nd := o.Apply(es).Dict()
+		assert.NotSame(t, d, nd, "prepared again after reload")
+		assert.Equal(t, "otro", nd.Get("other").Value())
+		assert.Equal(t, "tenant", nd.Get("foo").Value())
+	})
+
+	t.Run("add", func(t *testing.T) {
+		d := i18n.NewDict()
+		d.Add("baz", i18n.M{"qux": "tenant qux"})
+		o.Add("es", d)
+		l := o.Apply(es)
+		assert.Equal(t, "tenant qux", l.T("baz.qux"))
+		assert.Equal(t, "tenant", l.T("foo"))
+	})
+
+	t.Run("concurrency", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 50; j++ {
+					assert.Equal(t, "tenant", i18n.T(ctx, "foo"))
+				}
+			}()
+		}
+		wg.Wait()
+	})
+}
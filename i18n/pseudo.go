@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Pseudo locale codes, following the conventions used by Android and
+// other platforms.
+const (
+	// PseudoAccentedRegion is appended to the base code of the source
+	// locale for accented and expanded pseudo locales, like `en-XA`.
+	PseudoAccentedRegion = "XA"
+	// PseudoBidiCode is used for the right-to-left pseudo locale.
+	PseudoBidiCode Code = "ar-XB"
+)
+
+const (
+	bidiMark     = "\u200f" // right-to-left mark
+	bidiOverride = "\u202e" // right-to-left override
+	bidiPop      = "\u202c" // pop directional formatting
+)
+
+// pseudoExpansion is the proportion of extra text added to accented
+// texts, enough to highlight layouts that will not fit longer languages.
+const pseudoExpansion = 0.4
+
+var (
+	// formatRegexp matches both `%{key}` placeholders and `fmt` verbs, which
+	// must not be modified.
+	formatRegexp = regexp.MustCompile(`%\{[^}]+\}|%(?:\[\d+\])?[-+# 0]*(?:\d+|\*)?(?:\.(?:\d+|\*))?[a-zA-Z%]`)
+
+	pseudoAccents = map[rune]rune{
+		'a': 'á', 'b': 'ƀ', 'c': 'ç', 'd': 'ð', 'e': 'é', 'f': 'ƒ', 'g': 'ĝ',
+		'h': 'ĥ', 'i': 'î', 'j': 'ĵ', 'k': 'ķ', 'l': 'ļ', 'm': 'ɱ', 'n': 'ñ',
+		'o': 'ö', 'p': 'þ', 'q': 'ǫ', 'r': 'ŕ', 's': 'š', 't': 'ţ', 'u': 'û',
+		'v': 'ṽ', 'w': 'ŵ', 'x': 'ẋ', 'y': 'ý', 'z': 'ž',
+		'A': 'Å', 'B': 'Ɓ', 'C': 'Ç', 'D': 'Ð', 'E': 'É', 'F': 'Ƒ', 'G': 'Ĝ',
+		'H': 'Ĥ', 'I': 'Î', 'J': 'Ĵ', 'K': 'Ķ', 'L': 'Ļ', 'M': 'Ṁ', 'N': 'Ñ',
+		'O': 'Ö', 'P': 'Þ', 'Q': 'Ǫ', 'R': 'Ŕ', 'S': 'Š', 'T': 'Ţ', 'U': 'Û',
+		'V': 'Ṽ', 'W': 'Ŵ', 'X': 'Ẋ', 'Y': 'Ý', 'Z': 'Ž',
+	}
+	pseudoPadding = strings.Fields("one two three four five six seven eight nine ten")
+)
+
+// PseudoAccented derives a pseudo locale from the source, with a code like
+// `en-XA`, where every text is accented, expanded, and surrounded by
+// brackets. This makes it easy to spot texts that were not translated,
+// are truncated, or have been concatenated. Placeholders and `fmt` verbs
+// are not modified.
+func PseudoAccented(l *Locale) *Locale {
+	code := Code(l.code.Base().String() + "-" + PseudoAccentedRegion)
+	return newPseudoLocale(l, code, accentText)
+}
+
+// PseudoBidi derives a right-to-left pseudo locale from the source with
+// the `ar-XB` code, where every word is forced to be displayed in reverse
+// order. This helps find layouts that do not support right-to-left
+// languages. Placeholders and `fmt` verbs are not modified.
+func PseudoBidi(l *Locale) *Locale {
+	return newPseudoLocale(l, PseudoBidiCode, bidiText)
+}
+
+func newPseudoLocale(l *Locale, code Code, fn func(string) string) *Locale {
+	pl := &Locale{
+		code: code,
+		rule: l.rule,
+	}
+	pl.dict.Store(pseudoDict(l.Dict(), fn))
+	return pl
+}
+
+// pseudoDict prepares a copy of the dictionary with the function applied to
+// every text.
+func pseudoDict(d *Dict, fn func(string) string) *Dict {
+	if d == nil {
+		return nil
+	}
+	nd := &Dict{
+		value: d.value,
+		data:  d.data,
+	}
+	switch v := d.data.(type) {
+	case nil:
+		if d.value != "" {
+			nd.value = fn(d.value)
+		}
+	case []*Dict:
+		list := make([]*Dict, len(v))
+		for i, row := range v {
+			list[i] = pseudoDict(row, fn)
+		}
+		nd.data = list
+	}
+	if d.entries != nil {
+		nd.entries = make(map[string]*Dict, len(d.entries))
+		for k, v := range d.entries {
+			nd.entries[k] = pseudoDict(v, fn)
+		}
+	}
+	return nd
+}
+
+// mapText applies the function to all the parts of the text that are not
+// placeholders or `fmt` verbs.
+func mapText(s string, fn func(string) string) string {
+	var b strings.Builder
+	last := 0
+	for _, loc := range formatRegexp.FindAllStringIndex(s, -1) {
+		b.WriteString(fn(s[last:loc[0]]))
+		b.WriteString(s[loc[0]:loc[1]])
+		last = loc[1]
+	}
+	b.WriteString(fn(s[last:]))
+	return b.String()
+}
+
+func accentText(s string) string {
+	out := mapText(s, func(txt string) string {
+		return strings.Map(func(r rune) rune {
+			if a, ok := pseudoAccents[r]; ok {
+				return a
+			}
+			return r
+		}, txt)
+	})
+
+	extra := int(float64(utf8.RuneCountInString(s))*pseudoExpansion + 0.5)
+	var pad []string
+	for n, i := 0, 0; n < extra; i++ {
+		w := pseudoPadding[i%len(pseudoPadding)]
+		pad = append(pad, w)
+		n += len(w) + 1
+	}
+	if len(pad) > 0 {
+		out = out + " " + strings.Join(pad, " ")
+	}
+	return "[" + out + "]"
+}
+
+func bidiText(s string) string {
+	out := mapText(s, func(txt string) string {
+		var b strings.Builder
+		word := false
+		for _, r := range txt {
+			space := r == ' ' || r == '\t' || r == '\n'
+			switch {
+			case !space && !word:
+				b.WriteString(bidiOverride)
+				word = true
+			case space && word:
+				b.WriteString(bidiPop)
+				word = false
+			}
+			b.WriteRune(r)
+		}
+		if word {
+			b.WriteString(bidiPop)
+		}
+		return b.String()
+	})
+	return bidiMark + out + bidiMark
+}
@@ -0,0 +1,57 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPseudoAccented(t *testing.T) {
+	l := i18n.NewLocale("en-US", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+	l.Dict().Add("fmt", "Hello %s, you have %5.2f%% and %[1]s")
+
+	pl := i18n.PseudoAccented(l)
+	assert.Equal(t, i18n.Code("en-XA"), pl.Code())
+	assert.Equal(t, "[ƀáŕ one]", pl.T("foo"))
+	assert.Equal(t, "[2 ɱîçé one two]", pl.N("baz.mice", 2, i18n.M{"count": 2}))
+	assert.Equal(t, "[1 ðûçķ one]", pl.N("baz.ducks", 1, 1))
+	assert.Equal(t, "[Ĥéļļö Sam, ýöû ĥáṽé 12.50% áñð Sam one two three]", pl.T("fmt", "Sam", 12.5))
+	assert.Equal(t, "[Šûñðáý one]", pl.List("date.day_names")[0])
+	assert.Equal(t, 2, pl.Int("number.precision"))
+	assert.True(t, pl.Bool("number.grouping"))
+	assert.Equal(t, "bar", l.T("foo"), "source unchanged")
+
+	long := pl.T("fmt", "Sam", 12.5)
+	assert.Greater(t, len([]rune(long)), len([]rune(l.T("fmt", "Sam", 12.5)))*13/10)
+}
+
+func TestPseudoBidi(t *testing.T) {
+	l := i18n.NewLocale("en", nil)
+	require.NoError(t, json.Unmarshal(SampleLocaleData(), l))
+
+	pl := i18n.PseudoBidi(l)
+	assert.Equal(t, i18n.Code("ar-XB"), pl.Code())
+	assert.Equal(t, "\u200f\u202ebar\u202c\u200f", pl.T("foo"))
+	out := pl.N("baz.mice", 2, i18n.M{"count": 2})
+	assert.Equal(t, "\u200f2 \u202emice\u202c\u200f", out)
+	assert.Equal(t, "2 mice", strings.NewReplacer("\u200f", "", "\u202e", "", "\u202c", "").Replace(out))
+}
+
+func TestPseudoRegister(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, json.Unmarshal(SampleLocales(), ls))
+	require.NoError(t, ls.Add(i18n.PseudoAccented(ls.Get("en"))))
+	require.NoError(t, ls.Add(i18n.PseudoBidi(ls.Get("en"))))
+
+	l := ls.Match("en-XA")
+	require.NotNil(t, l)
+	ctx := l.WithContext(context.Background())
+	assert.Equal(t, "[ƀáŕ one]", i18n.T(ctx, "foo"))
+	assert.NotNil(t, ls.Match("ar-XB"))
+}
@@ -0,0 +1,218 @@
+package i18n
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultReloadInterval is the period between checks for changes used by
+// the reloader if no other interval is provided.
+const DefaultReloadInterval = 2 * time.Second
+
+// Reloader keeps a set of locales up to date with the files contained in a
+// File System. Each time the files change, a completely new Locales instance
+// is loaded and swapped atomically with the current one, so that any Locale
+// objects already in use remain untouched.
+type Reloader struct {
+	src           fs.FS
+	opts          []LoadOption
+	defaultLocale Code
+	interval      time.Duration
+	onReload      []func(*Locales)
+	onError       []func(error)
+
+	current atomic.Pointer[Locales]
+	mu      sync.Mutex // serializes reloads
+	stamp   string
+}
+
+// ReloaderOption is used to configure a Reloader.
+type ReloaderOption func(*Reloader)
+
+// ReloadEvery sets the interval between checks for changes in the files
+// used by the Watch method, which must be greater than zero.
+func ReloadEvery(d time.Duration) ReloaderOption {
+	return func(r *Reloader) {
+		r.interval = d
+	}
+}
+
+// ReloadWithDefault ensures the locales are loaded using LoadWithDefault
+// with the provided default locale code.
+func ReloadWithDefault(code Code) ReloaderOption {
+	return func(r *Reloader) {
+		r.defaultLocale = code
+	}
+}
+
+// ReloadOptions defines the load options to use each time the files are
+// loaded.
+func ReloadOptions(opts ...LoadOption) ReloaderOption {
+	return func(r *Reloader) {
+		r.opts = append(r.opts, opts...)
+	}
+}
+
+// OnReload adds a function to call with the new set of locales after
+// each successful reload.
+func OnReload(fn func(*Locales)) ReloaderOption {
+	return func(r *Reloader) {
+		r.onReload = append(r.onReload, fn)
+	}
+}
+
+// OnReloadError adds a function to call when a reload fails, in which
+// case the previous set of locales will continue to be used.
+func OnReloadError(fn func(error)) ReloaderOption {
+	return func(r *Reloader) {
+		r.onError = append(r.onError, fn)
+	}
+}
+
+// NewReloader prepares a new reloader for the provided File System and
+// performs the initial load, which must succeed.
+func NewReloader(src fs.FS, opts ...ReloaderOption) (*Reloader, error) {
+	r := &Reloader{
+		src:      src,
+		interval: DefaultReloadInterval,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.interval <= 0 {
+		return nil, fmt.Errorf("invalid reload interval: %v", r.interval)
+	}
+	stamp, err := r.fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	ls, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	r.stamp = stamp
+	r.current.Store(ls)
+	return r, nil
+}
+
+// Locales provides the current set of locales.
+func (r *Reloader) Locales() *Locales {
+	return r.current.Load()
+}
+
+// Reload will load the files into a new set of locales and swap them with
+// the current set if successful.
+func (r *Reloader) Reload() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := r.fingerprint()
+	if err != nil {
+		return r.fail(err)
+	}
+	return r.reload(stamp)
+}
+
+// Poll checks to see if any of the files have changed since the last load
+// and reloads them if so. The response indicates if a new set of locales
+// is now in use.
+func (r *Reloader) Poll() (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stamp, err := r.fingerprint()
+	if err != nil {
+		return false, r.fail(err)
+	}
+	if stamp == r.stamp {
+		return false, nil
+	}
+	if err := r.reload(stamp); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Watch polls the files for changes at the configured interval until the
+// context is cancelled. Errors are reported to the OnReloadError functions.
+func (r *Reloader) Watch(ctx context.Context) {
+	t := time.NewTicker(r.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			_, _ = r.Poll()
+		}
+	}
+}
+
+func (r *Reloader) reload(stamp string) error {
+	ls, err := r.load()
+	if err != nil {
+		return r.fail(err)
+	}
+	r.stamp = stamp
+	r.current.Store(ls)
+	for _, fn := range r.onReload {
+		fn(ls)
+	}
+	return nil
+}
+
+func (r *Reloader) load() (*Locales, error) {
+	ls := new(Locales)
+	if r.defaultLocale != "" {
+		if err := ls.LoadWithDefault(r.src, r.defaultLocale, r.opts...); err != nil {
+			return nil, err
+		}
+		return ls, nil
+	}
+	if err := ls.Load(r.src, r.opts...); err != nil {
+		return nil, err
+	}
+	return ls, nil
+}
+
+func (r *Reloader) fail(err error) error {
+	err = fmt.Errorf("reloading locales: %w", err)
+	for _, fn := range r.onError {
+		fn(err)
+	}
+	return err
+}
+
+// fingerprint provides a hash of the names, sizes, and modification times
+// of all the files that would be loaded.
+func (r *Reloader) fingerprint() (string, error) {
+	h := sha256.New()
+	err := fs.WalkDir(r.src, ".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return fmt.Errorf("walking directory: %w", err)
+		}
+		switch filepath.Ext(path) {
+		case ".yaml", ".yml", ".json":
+			// good
+		default:
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return fmt.Errorf("reading file info '%s': %w", path, err)
+		}
+		fmt.Fprintf(h, "%s:%d:%d\n", path, info.Size(), info.ModTime().UnixNano())
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,126 @@
+package i18n_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeLocaleFile(t *testing.T, dir, name, data string, mod time.Time) {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o644))
+	require.NoError(t, os.Chtimes(path, mod, mod))
+}
+
+func TestReloader(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeLocaleFile(t, dir, "en.yaml", "en:\n  foo: \"bar\"\n", now)
+
+	var reloaded []*i18n.Locales
+	var errs []error
+	r, err := i18n.NewReloader(os.DirFS(dir),
+		i18n.OnReload(func(ls *i18n.Locales) {
+			reloaded = append(reloaded, ls)
+		}),
+		i18n.OnReloadError(func(err error) {
+			errs = append(errs, err)
+		}),
+	)
+	require.NoError(t, err)
+	ls := r.Locales()
+	require.NotNil(t, ls)
+	en := ls.Get("en")
+	assert.Equal(t, "bar", en.T("foo"))
+
+	t.Run("unchanged", func(t *testing.T) {
+		ok, err := r.Poll()
+		require.NoError(t, err)
+		assert.False(t, ok)
+		assert.Same(t, ls, r.Locales())
+		assert.Empty(t, reloaded)
+	})
+
+	t.Run("changed", func(t *testing.T) {
+		ctx := en.WithContext(context.Background())
+		writeLocaleFile(t, dir, "en.yaml", "en:\n  foo: \"baz\"\n", now.Add(time.Second))
+		ok, err := r.Poll()
+		require.NoError(t, err)
+		assert.True(t, ok)
+		require.Len(t, reloaded, 1)
+		assert.Same(t, reloaded[0], r.Locales())
+		assert.Equal(t, "baz", r.Locales().Get("en").T("foo"))
+		assert.Equal(t, "bar", i18n.T(ctx, "foo"), "in-flight locale unchanged")
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		current := r.Locales()
+		writeLocaleFile(t, dir, "es.yaml", "es: [bad\n", now.Add(2*time.Second))
+		ok, err := r.Poll()
+		assert.ErrorContains(t, err, "reloading locales: unmarshalling file 'es.yaml'")
+		assert.False(t, ok)
+		require.Len(t, errs, 1)
+		assert.Equal(t, err, errs[0])
+		assert.Same(t, current, r.Locales())
+	})
+
+	t.Run("forced", func(t *testing.T) {
+		writeLocaleFile(t, dir, "es.yaml", "es:\n  foo: \"bara\"\n", now.Add(3*time.Second))
+		require.NoError(t, r.Reload())
+		assert.Equal(t, "bara", r.Locales().Get("es").T("foo"))
+	})
+}
+
+func TestReloaderOptions(t *testing.T) {
+	r, err := i18n.NewReloader(examples.Content, i18n.ReloadWithDefault("en"))
+	require.NoError(t, err)
+	assert.Equal(t, "Special Label", r.Locales().Get("es").T("special_label"))
+
+	_, err = i18n.NewReloader(examples.Content, i18n.ReloadWithDefault("bad"))
+	assert.ErrorContains(t, err, "undefined default locale: bad")
+
+	_, err = i18n.NewReloader(examples.Content, i18n.ReloadEvery(0))
+	assert.EqualError(t, err, "invalid reload interval: 0s")
+
+	_, err = i18n.NewReloader(examples.Content, i18n.ReloadEvery(-time.Second))
+	assert.EqualError(t, err, "invalid reload interval: -1s")
+
+	r, err = i18n.NewReloader(examples.Content, i18n.ReloadOptions(i18n.Strict()))
+	require.NoError(t, err)
+	assert.Equal(t, "Log In", r.Locales().Get("en").T("login.button"))
+}
+
+func TestReloaderWatch(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	writeLocaleFile(t, dir, "en.yaml", "en:\n  foo: \"bar\"\n", now)
+
+	done := make(chan *i18n.Locales, 1)
+	r, err := i18n.NewReloader(os.DirFS(dir),
+		i18n.ReloadEvery(5*time.Millisecond),
+		i18n.OnReload(func(ls *i18n.Locales) {
+			done <- ls
+		}),
+	)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Watch(ctx)
+
+	writeLocaleFile(t, dir, "en.yaml", "en:\n  foo: \"baz\"\n", now.Add(time.Second))
+	select {
+	case ls := <-done:
+		assert.Equal(t, "baz", ls.Get("en").T("foo"))
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for reload")
+	}
+}
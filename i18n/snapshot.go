@@ -0,0 +1,298 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"hash/crc32"
+	"sort"
+)
+
+// Snapshots are a compact binary representation of a set of locales that
+// can be prepared at build time and loaded without parsing any YAML or JSON.
+// The layout is:
+//
+//	magic    [4]byte  "CI18"
+//	version  uint16   snapshot format version
+//	reserved uint16
+//	length   uint32   payload length
+//	checksum uint32   CRC-32 (IEEE) of the payload
+//	payload:
+//	  strings  uvarint count, uvarint length of each, concatenated data
+//	  locales  uvarint count, then for each: uvarint code index, node
+//
+// Each node starts with a flags byte, followed by the value's string index
+// if present, the list nodes if present, and the map entries as pairs of key
+// string index and node if present. All integers are little-endian.
+const (
+	snapshotMagic      = "CI18"
+	snapshotVersion    = 1
+	snapshotHeaderSize = 16
+)
+
+const (
+	nodeValue byte = 1 << iota
+	nodeEntries
+	nodeList
+	nodeBool
+	nodeNumber
+)
+
+// Snapshot errors.
+var (
+	// ErrSnapshotInvalid is used when the data is not a snapshot or has
+	// been truncated.
+	ErrSnapshotInvalid = errors.New("invalid snapshot")
+	// ErrSnapshotVersion is used when the snapshot was prepared with an
+	// incompatible version of the format.
+	ErrSnapshotVersion = errors.New("unsupported snapshot version")
+	// ErrSnapshotChecksum is used when the snapshot's contents do not match
+	// the checksum in the header.
+	ErrSnapshotChecksum = errors.New("snapshot checksum mismatch")
+)
+
+// MarshalBinary prepares a snapshot of all the locales. The output is
+// deterministic so it may be safely committed or cached.
+func (ls *Locales) MarshalBinary() ([]byte, error) {
+	ls.mu.RLock()
+	list := make([]*Locale, len(ls.list))
+	copy(list, ls.list)
+	ls.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].code < list[j].code
+	})
+
+	e := &snapshotEncoder{index: make(map[string]int)}
+	body := new(bytes.Buffer)
+	e.uvarint(body, len(list))
+	for _, l := range list {
+		e.uvarint(body, e.str(l.code.String()))
+		e.node(body, l.Dict())
+	}
+
+	payload := new(bytes.Buffer)
+	e.uvarint(payload, len(e.strs))
+	for _, s := range e.strs {
+		e.uvarint(payload, len(s))
+	}
+	for _, s := range e.strs {
+		payload.WriteString(s)
+	}
+	payload.Write(body.Bytes())
+
+	out := make([]byte, snapshotHeaderSize, snapshotHeaderSize+payload.Len())
+	copy(out, snapshotMagic)
+	binary.LittleEndian.PutUint16(out[4:], snapshotVersion)
+	binary.LittleEndian.PutUint32(out[8:], uint32(payload.Len()))
+	binary.LittleEndian.PutUint32(out[12:], crc32.ChecksumIEEE(payload.Bytes()))
+	return append(out, payload.Bytes()...), nil
+}
+
+// UnmarshalBinary loads the locales contained in a snapshot and merges
+// them into any existing locales. Snapshots that were prepared with a
+// different version of the format or that are corrupted will be rejected.
+func (ls *Locales) UnmarshalBinary(data []byte) error {
+	if len(data) < snapshotHeaderSize || string(data[:4]) != snapshotMagic {
+		return ErrSnapshotInvalid
+	}
+	if binary.LittleEndian.Uint16(data[4:]) != snapshotVersion {
+		return ErrSnapshotVersion
+	}
+	size := binary.LittleEndian.Uint32(data[8:])
+	payload := data[snapshotHeaderSize:]
+	if uint64(len(payload)) != uint64(size) {
+		return ErrSnapshotInvalid
+	}
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(data[12:]) {
+		return ErrSnapshotChecksum
+	}
+
+	d := &snapshotDecoder{data: payload}
+	d.strings()
+	n := d.uvarint()
+	aux := make(map[Code]*Dict, n)
+	for i := 0; i < n && d.err == nil; i++ {
+		code := Code(d.str())
+		aux[code] = d.node()
+	}
+	if d.err != nil {
+		return d.err
+	}
+	if d.pos != len(d.data) {
+		return ErrSnapshotInvalid
+	}
+
+	ds := newDicts()
+	for _, c := range sortedCodes(aux) {
+		ds.merge(c, aux[c], false)
+	}
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+	ls.apply(ds, false)
+	ls.rebuild()
+	return nil
+}
+
+type snapshotEncoder struct {
+	strs  []string
+	index map[string]int
+	buf   [binary.MaxVarintLen64]byte
+}
+
+func (e *snapshotEncoder) str(s string) int {
+	if i, ok := e.index[s]; ok {
+		return i
+	}
+	i := len(e.strs)
+	e.strs = append(e.strs, s)
+	e.index[s] = i
+	return i
+}
+
+func (e *snapshotEncoder) uvarint(w *bytes.Buffer, n int) {
+	l := binary.PutUvarint(e.buf[:], uint64(n))
+	w.Write(e.buf[:l])
+}
+
+func (e *snapshotEncoder) node(w *bytes.Buffer, d *Dict) {
+	if d == nil {
+		w.WriteByte(0)
+		return
+	}
+	var flags byte
+	if d.value != "" {
+		flags |= nodeValue
+	}
+	if d.entries != nil {
+		flags |= nodeEntries
+	}
+	list, isList := d.data.([]*Dict)
+	switch d.data.(type) {
+	case []*Dict:
+		flags |= nodeList
+	case bool:
+		flags |= nodeBool
+	case json.Number:
+		flags |= nodeNumber
+	}
+	w.WriteByte(flags)
+
+	if flags&nodeValue != 0 {
+		e.uvarint(w, e.str(d.value))
+	}
+	if isList {
+		e.uvarint(w, len(list))
+		for _, row := range list {
+			e.node(w, row)
+		}
+	}
+	if flags&nodeEntries != 0 {
+		keys := d.Keys()
+		e.uvarint(w, len(keys))
+		for _, k := range keys {
+			e.uvarint(w, e.str(k))
+			e.node(w, d.entries[k])
+		}
+	}
+}
+
+type snapshotDecoder struct {
+	data []byte
+	pos  int
+	strs []string
+	err  error
+}
+
+// strings reads the string table, copying all the data into a single
+// string that each entry will reference.
+func (d *snapshotDecoder) strings() {
+	n := d.uvarint()
+	lens := make([]int, 0, min(n, len(d.data)))
+	total := 0
+	for i := 0; i < n && d.err == nil; i++ {
+		l := d.uvarint()
+		lens = append(lens, l)
+		total += l
+	}
+	if d.err != nil {
+		return
+	}
+	if total < 0 || total > len(d.data)-d.pos {
+		d.err = ErrSnapshotInvalid
+		return
+	}
+	all := string(d.data[d.pos : d.pos+total])
+	d.pos += total
+	d.strs = make([]string, len(lens))
+	off := 0
+	for i, l := range lens {
+		d.strs[i] = all[off : off+l]
+		off += l
+	}
+}
+
+func (d *snapshotDecoder) uvarint() int {
+	if d.err != nil {
+		return 0
+	}
+	n, l := binary.Uvarint(d.data[d.pos:])
+	if l <= 0 || n > uint64(len(d.data)) {
+		d.err = ErrSnapshotInvalid
+		return 0
+	}
+	d.pos += l
+	return int(n)
+}
+
+func (d *snapshotDecoder) str() string {
+	i := d.uvarint()
+	if d.err != nil {
+		return ""
+	}
+	if i >= len(d.strs) {
+		d.err = ErrSnapshotInvalid
+		return ""
+	}
+	return d.strs[i]
+}
+
+func (d *snapshotDecoder) node() *Dict {
+	if d.err != nil {
+		return nil
+	}
+	if d.pos >= len(d.data) {
+		d.err = ErrSnapshotInvalid
+		return nil
+	}
+	flags := d.data[d.pos]
+	d.pos++
+
+	nd := new(Dict)
+	if flags&nodeValue != 0 {
+		nd.value = d.str()
+	}
+	switch {
+	case flags&nodeBool != 0:
+		nd.data = nd.value == "true"
+	case flags&nodeNumber != 0:
+		nd.data = json.Number(nd.value)
+	case flags&nodeList != 0:
+		n := d.uvarint()
+		list := make([]*Dict, 0, min(n, len(d.data)))
+		for i := 0; i < n && d.err == nil; i++ {
+			list = append(list, d.node())
+		}
+		nd.data = list
+	}
+	if flags&nodeEntries != 0 {
+		n := d.uvarint()
+		nd.entries = make(map[string]*Dict, min(n, len(d.data)))
+		for i := 0; i < n && d.err == nil; i++ {
+			k := d.str()
+			nd.entries[k] = d.node()
+		}
+	}
+	return nd
+}
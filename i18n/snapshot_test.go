@@ -0,0 +1,90 @@
+package i18n_test
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalesSnapshot(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.Load(examples.Content))
+	require.NoError(t, json.Unmarshal(SampleLocales(), ls))
+
+	data, err := ls.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, "CI18", string(data[:4]))
+
+	again, err := ls.MarshalBinary()
+	require.NoError(t, err)
+	assert.Equal(t, data, again, "deterministic")
+
+	ls2 := new(i18n.Locales)
+	require.NoError(t, ls2.UnmarshalBinary(data))
+	assert.ElementsMatch(t, ls.Codes(), ls2.Codes())
+	en := ls2.Get("en")
+	require.NotNil(t, en)
+	assert.Equal(t, "Log In", en.T("login.button"))
+	assert.Equal(t, "Extensions", en.T("ext.test"))
+	assert.Equal(t, "2 mice", en.N("baz.plural", 2, i18n.M{"count": 2}))
+	assert.Equal(t, "Monday", en.List("date.day_names")[1])
+	assert.Equal(t, 2, en.Int("number.precision"))
+	assert.Equal(t, "Iniciar Sesión", ls2.Get("es").T("login.button"))
+
+	t.Run("typed values", func(t *testing.T) {
+		src := new(i18n.Locales)
+		require.NoError(t, json.Unmarshal([]byte(`{"en":{"t":true,"f":false,"n":1.5,"l":[["a"],{"b":"c"}]}}`), src))
+		data, err := src.MarshalBinary()
+		require.NoError(t, err)
+		dst := new(i18n.Locales)
+		require.NoError(t, dst.UnmarshalBinary(data))
+		en := dst.Get("en")
+		assert.True(t, en.Bool("t"))
+		assert.False(t, en.Bool("f"))
+		assert.True(t, en.Has("f"))
+		assert.Equal(t, 1.5, en.Float("n"))
+		assert.Equal(t, []string{"", ""}, en.List("l"))
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, json.Unmarshal([]byte(`{"en":{"login":{"button":"Enter"}}}`), ls))
+		require.NoError(t, ls.UnmarshalBinary(data))
+		assert.Equal(t, "Enter", ls.Get("en").T("login.button"))
+		assert.Equal(t, "Sign Up", ls.Get("en").T("login.signup-button"))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		assert.ErrorIs(t, ls.UnmarshalBinary(nil), i18n.ErrSnapshotInvalid)
+		assert.ErrorIs(t, ls.UnmarshalBinary([]byte("not a snapshot at all")), i18n.ErrSnapshotInvalid)
+		assert.ErrorIs(t, ls.UnmarshalBinary(data[:len(data)-1]), i18n.ErrSnapshotInvalid)
+	})
+
+	t.Run("version", func(t *testing.T) {
+		bad := append([]byte{}, data...)
+		binary.LittleEndian.PutUint16(bad[4:], 99)
+		assert.ErrorIs(t, new(i18n.Locales).UnmarshalBinary(bad), i18n.ErrSnapshotVersion)
+	})
+
+	t.Run("checksum", func(t *testing.T) {
+		bad := append([]byte{}, data...)
+		bad[len(bad)-3] ^= 0xff
+		assert.ErrorIs(t, new(i18n.Locales).UnmarshalBinary(bad), i18n.ErrSnapshotChecksum)
+	})
+
+	t.Run("truncated payload", func(t *testing.T) {
+		for i := 16; i < len(data); i++ {
+			bad := append([]byte{}, data[:i]...)
+			binary.LittleEndian.PutUint32(bad[8:], uint32(i-16))
+			binary.LittleEndian.PutUint32(bad[12:], crc32.ChecksumIEEE(bad[16:]))
+			assert.ErrorIs(t, new(i18n.Locales).UnmarshalBinary(bad), i18n.ErrSnapshotInvalid, "length %d", i)
+		}
+	})
+}
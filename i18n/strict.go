@@ -0,0 +1,163 @@
+package i18n
+
+import (
+	"errors"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Errors reported by strict loading, wrapped inside a LoadError.
+var (
+	// ErrDuplicateKey is used when the same key is defined more than once.
+	ErrDuplicateKey = errors.New("duplicate key")
+	// ErrKeyConflict is used when a key is defined as a text in one place
+	// and as a map in another.
+	ErrKeyConflict = errors.New("conflicting key")
+	// ErrInvalidValue is used when a key's value is not supported.
+	ErrInvalidValue = errors.New("invalid value")
+)
+
+// LoadError describes a problem found in a specific position of a
+// source file while loading in strict mode.
+type LoadError struct {
+	// Path of the file containing the problem.
+	Path string
+	// Line inside the file where the problem was found.
+	Line int
+	// Key is the complete key, including the locale code.
+	Key string
+	// Prev contains the position, as `path:line`, of the previous
+	// definition of the key, if any.
+	Prev string
+	// Err is the underlying cause, like ErrDuplicateKey.
+	Err error
+}
+
+// Error provides the error message including the position.
+func (e *LoadError) Error() string {
+	msg := fmt.Sprintf("%s:%d: %s: %s", e.Path, e.Line, e.Key, e.Err)
+	if e.Prev != "" {
+		msg = fmt.Sprintf("%s (previously defined at %s)", msg, e.Prev)
+	}
+	return msg
+}
+
+// Unwrap provides the underlying cause of the error.
+func (e *LoadError) Unwrap() error {
+	return e.Err
+}
+
+// checker keeps track of where each key was defined across multiple files
+// so that problems can be reported.
+type checker struct {
+	override bool
+	defs     map[string]*definition
+	errs     []error
+}
+
+type definition struct {
+	pos  string
+	leaf bool
+}
+
+func newChecker(override bool) *checker {
+	return &checker{
+		override: override,
+		defs:     make(map[string]*definition),
+	}
+}
+
+// check parses the provided data and looks for problems, using the prefix
+// to build the complete key of each definition. Problems are kept until `err`
+// is called, and the response will be false if any were found in the file.
+// An error is only returned if the data could not be parsed.
+func (c *checker) check(path string, data []byte, prefix string) (bool, error) {
+	doc := new(yaml.Node)
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return false, err
+	}
+	if len(doc.Content) == 0 {
+		return true, nil // empty
+	}
+	count := len(c.errs)
+	c.walk(path, prefix, doc.Content[0])
+	return len(c.errs) == count, nil
+}
+
+func (c *checker) walk(path, prefix string, n *yaml.Node) {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	if n.Kind != yaml.MappingNode {
+		c.add(path, n.Line, prefix, ErrInvalidValue, "")
+		return
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		key := k.Value
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		if v.Kind == yaml.AliasNode {
+			v = v.Alias
+		}
+		leaf := v.Kind != yaml.MappingNode
+		pos := fmt.Sprintf("%s:%d", path, k.Line)
+		if def, ok := c.defs[key]; ok {
+			switch {
+			case def.leaf != leaf:
+				c.add(path, k.Line, key, ErrKeyConflict, def.pos)
+				continue
+			case leaf && !c.override:
+				c.add(path, k.Line, key, ErrDuplicateKey, def.pos)
+				continue
+			}
+		} else {
+			c.defs[key] = &definition{pos: pos, leaf: leaf}
+		}
+		if leaf {
+			c.checkValue(path, key, v)
+			continue
+		}
+		c.walk(path, key, v)
+	}
+}
+
+// checkValue reports nulls and, inside lists, any item that is not a
+// plain scalar value.
+func (c *checker) checkValue(path, key string, v *yaml.Node) {
+	switch v.Kind {
+	case yaml.ScalarNode:
+		if v.Tag == "!!null" {
+			c.add(path, v.Line, key, ErrInvalidValue, "")
+		}
+	case yaml.SequenceNode:
+		for _, item := range v.Content {
+			if item.Kind == yaml.AliasNode {
+				item = item.Alias
+			}
+			if item.Kind != yaml.ScalarNode || item.Tag == "!!null" {
+				c.add(path, item.Line, key, ErrInvalidValue, "")
+			}
+		}
+	}
+}
+
+func (c *checker) add(path string, line int, key string, err error, prev string) {
+	c.errs = append(c.errs, &LoadError{
+		Path: path,
+		Line: line,
+		Key:  key,
+		Prev: prev,
+		Err:  err,
+	})
+}
+
+// err provides all the problems found joined into a single error.
+func (c *checker) err() error {
+	if c == nil {
+		return nil
+	}
+	return errors.Join(c.errs...)
+}
@@ -0,0 +1,149 @@
+package i18n_test
+
+import (
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/internal/examples"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadStrict(t *testing.T) {
+	t.Run("valid", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(examples.Content, i18n.Strict()))
+		assert.Equal(t, "Log In", ls.Get("en").T("login.button"))
+	})
+
+	t.Run("duplicate keys", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\n")},
+			"b.json": {Data: []byte("{\n  \"en\": {\n    \"foo\": \"baz\"\n  }\n}")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		require.Error(t, err)
+		assert.ErrorIs(t, err, i18n.ErrDuplicateKey)
+		assert.EqualError(t, err, "b.json:3: en.foo: duplicate key (previously defined at a.yaml:2)")
+		assert.Equal(t, "bar", ls.Get("en").T("foo"), "first value kept")
+
+		var le *i18n.LoadError
+		require.True(t, errors.As(err, &le))
+		assert.Equal(t, "b.json", le.Path)
+		assert.Equal(t, 3, le.Line)
+		assert.Equal(t, "en.foo", le.Key)
+	})
+
+	t.Run("conflicting keys", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\n")},
+			"b.yaml": {Data: []byte("en:\n  foo:\n    bar: \"baz\"\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.ErrorIs(t, err, i18n.ErrKeyConflict)
+		assert.EqualError(t, err, "b.yaml:2: en.foo: conflicting key (previously defined at a.yaml:2)")
+	})
+
+	t.Run("invalid values", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: ~\n  bar: \"ok\"\n  baz:\n  qux: [1, 2]\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.ErrorIs(t, err, i18n.ErrInvalidValue)
+		assert.EqualError(t, err, "a.yaml:2: en.foo: invalid value\na.yaml:4: en.baz: invalid value")
+	})
+
+	t.Run("invalid list items", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo:\n    - \"ok\"\n    - bar: \"baz\"\n    - [1, 2]\n    - ~\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.ErrorIs(t, err, i18n.ErrInvalidValue)
+		assert.EqualError(t, err, "a.yaml:4: en.foo: invalid value\na.yaml:5: en.foo: invalid value\na.yaml:6: en.foo: invalid value")
+	})
+
+	t.Run("inferred locale", func(t *testing.T) {
+		src := fstest.MapFS{
+			"es/checkout.yaml": {Data: []byte("pay: \"Pagar\"\n")},
+			"es/es.yaml":       {Data: []byte("checkout:\n  pay: \"Pague\"\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.NamespaceFromFile(), i18n.Strict())
+		assert.EqualError(t, err, "es/es.yaml:2: es.checkout.pay: duplicate key (previously defined at es/checkout.yaml:1)")
+	})
+
+	t.Run("duplicate in file", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\n  foo: \"baz\"\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.EqualError(t, err, "a.yaml:3: en.foo: duplicate key (previously defined at a.yaml:2)")
+	})
+
+	t.Run("with default", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\nes:\n  baz: \"qux\"\n")},
+			"b.yaml": {Data: []byte("en:\n  foo: \"baz\"\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.ErrorIs(t, err, i18n.ErrDuplicateKey)
+		assert.Equal(t, "bar", ls.Get("en").T("foo"))
+
+		ls2 := new(i18n.Locales)
+		err2 := ls2.LoadWithDefault(src, "en", i18n.Strict())
+		assert.Equal(t, err.Error(), err2.Error(), "same problems reported")
+		assert.Equal(t, ls.Codes(), ls2.Codes(), "same locales loaded")
+		assert.Equal(t, "bar", ls2.Get("en").T("foo"))
+		assert.Equal(t, "bar", ls2.Get("es").T("foo"), "default merged")
+	})
+
+	t.Run("parse error", func(t *testing.T) {
+		src := fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: [\"bar\"\n")},
+		}
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Strict())
+		assert.ErrorContains(t, err, "checking file 'a.yaml'")
+	})
+}
+
+func TestLoadOverride(t *testing.T) {
+	src := fstest.MapFS{
+		"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\n  qux: \"quux\"\n  map:\n    a: \"b\"\n")},
+		"b.yaml": {Data: []byte("en:\n  foo: \"baz\"\n  qux:\n    a: \"b\"\n  map: \"c\"\n")},
+	}
+
+	t.Run("default", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		require.NoError(t, ls.Load(src, i18n.Override()))
+		l := ls.Get("en")
+		assert.Equal(t, "baz", l.T("foo"))
+		assert.Equal(t, "b", l.T("qux.a"))
+		assert.Equal(t, "c", l.T("map"))
+		assert.False(t, l.Has("map.a"))
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		ls := new(i18n.Locales)
+		err := ls.Load(src, i18n.Override(), i18n.Strict())
+		assert.NotErrorIs(t, err, i18n.ErrDuplicateKey)
+		assert.ErrorIs(t, err, i18n.ErrKeyConflict)
+		assert.Equal(t, "bar", ls.Get("en").T("foo"), "files with problems skipped")
+
+		ls = new(i18n.Locales)
+		err = ls.Load(fstest.MapFS{
+			"a.yaml": {Data: []byte("en:\n  foo: \"bar\"\n")},
+			"b.yaml": {Data: []byte("en:\n  foo: \"baz\"\n")},
+		}, i18n.Override(), i18n.Strict())
+		require.NoError(t, err)
+		assert.Equal(t, "baz", ls.Get("en").T("foo"))
+	})
+}
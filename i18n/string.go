@@ -0,0 +1,87 @@
+package i18n
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// String contains a text in multiple languages, typically stored alongside
+// other data like product names, independently of the locale files. Strings
+// are maps so they can be used directly with JSON and YAML.
+type String map[Code]string
+
+// In provides the text for the code, trying the code's base language if
+// not available. An empty string is returned if neither match.
+func (s String) In(code Code) string {
+	if t := s[code]; t != "" {
+		return t
+	}
+	return s[code.Base()]
+}
+
+// Match finds the text for the best matching locale from a string in the
+// "Accept-Language" header format, in the same way as `Locales.Match`.
+// An empty string is returned if none match.
+func (s String) Match(locale string) string {
+	for _, code := range ParseAcceptLanguage(locale) {
+		if t := s[code]; t != "" {
+			return t
+		}
+	}
+	return ""
+}
+
+// Localize provides the text for the locale in the context, as with In,
+// or for the context's default locale if not available.
+func (s String) Localize(ctx context.Context) string {
+	if l := GetLocale(ctx); l != nil {
+		if t := s.In(l.Code()); t != "" {
+			return t
+		}
+	}
+	if l := GetDefaultLocale(ctx); l != nil {
+		return s.In(l.Code())
+	}
+	return ""
+}
+
+// Codes provides the sorted list of codes with a text.
+func (s String) Codes() []Code {
+	codes := make([]Code, 0, len(s))
+	for c, t := range s {
+		if t != "" {
+			codes = append(codes, c)
+		}
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i] < codes[j]
+	})
+	return codes
+}
+
+// Missing provides the codes from the list that don't have a text.
+func (s String) Missing(codes ...Code) []Code {
+	var missing []Code
+	for _, c := range codes {
+		if s[c] == "" {
+			missing = append(missing, c)
+		}
+	}
+	return missing
+}
+
+// Validate checks that there is a text for every one of the codes, and
+// returns an error wrapping ErrMissingLocale otherwise.
+func (s String) Validate(codes ...Code) error {
+	missing := s.Missing(codes...)
+	if len(missing) == 0 {
+		return nil
+	}
+	list := make([]string, len(missing))
+	for i, c := range missing {
+		list[i] = c.String()
+	}
+	return fmt.Errorf("%w: %s", ErrMissingLocale, strings.Join(list, ", "))
+}
@@ -0,0 +1,76 @@
+package i18n_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/yaml"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestString(t *testing.T) {
+	s := i18n.String{
+		"en":    "Shoes",
+		"es":    "Zapatos",
+		"pt-BR": "Sapatos",
+		"fr":    "",
+	}
+
+	assert.Equal(t, "Zapatos", s.In("es"))
+	assert.Equal(t, "Zapatos", s.In("es-MX"), "base language")
+	assert.Equal(t, "Sapatos", s.In("pt-BR"))
+	assert.Equal(t, "", s.In("pt"))
+	assert.Equal(t, "", s.In("fr"))
+
+	assert.Equal(t, "Sapatos", s.Match("fr-FR,pt-BR;q=0.9,es;q=0.8"))
+	assert.Equal(t, "", s.Match("de"))
+
+	ctx := i18n.NewLocale("es", nil).WithContext(context.Background())
+	assert.Equal(t, "Zapatos", s.Localize(ctx))
+	assert.Equal(t, "", s.Localize(context.Background()))
+
+	assert.Equal(t, []i18n.Code{"en", "es", "pt-BR"}, s.Codes())
+
+	t.Run("default locale", func(t *testing.T) {
+		ctx := i18n.WithDefaultLocale(context.Background(), i18n.NewLocale("en", nil))
+		assert.Equal(t, "Shoes", s.Localize(ctx))
+		ctx = i18n.NewLocale("fr", nil).WithContext(ctx)
+		assert.Equal(t, "Shoes", s.Localize(ctx))
+		ctx = i18n.NewLocale("es-MX", nil).WithContext(ctx)
+		assert.Equal(t, "Zapatos", s.Localize(ctx))
+	})
+
+	t.Run("validate", func(t *testing.T) {
+		assert.NoError(t, s.Validate("en", "es"))
+		assert.Empty(t, s.Missing("en", "es"))
+		assert.Equal(t, []i18n.Code{"fr", "de"}, s.Missing("en", "fr", "de"))
+		err := s.Validate("en", "fr", "de")
+		assert.ErrorIs(t, err, i18n.ErrMissingLocale)
+		assert.EqualError(t, err, "locale not defined: fr, de")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		data, err := json.Marshal(i18n.String{"es": "Zapatos", "en": "Shoes"})
+		require.NoError(t, err)
+		assert.Equal(t, `{"en":"Shoes","es":"Zapatos"}`, string(data))
+
+		var out struct {
+			Name i18n.String `json:"name"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"name":{"en":"Shoes","es":"Zapatos"}}`), &out))
+		assert.Equal(t, "Zapatos", out.Name.In("es"))
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := yaml.Marshal(i18n.String{"es": "Zapatos", "en": "Shoes"})
+		require.NoError(t, err)
+		assert.Equal(t, "en: Shoes\nes: Zapatos\n", string(data))
+
+		var out i18n.String
+		require.NoError(t, yaml.Unmarshal(data, &out))
+		assert.Equal(t, "Shoes", out.In("en"))
+	})
+}
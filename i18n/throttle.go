@@ -0,0 +1,47 @@
+package i18n
+
+import (
+	"sync"
+	"time"
+)
+
+// throttle keeps track of when each locale and key was last reported.
+type throttle struct {
+	mu       sync.Mutex
+	interval time.Duration
+	pruned   time.Time
+	seen     map[throttleKey]time.Time
+}
+
+type throttleKey struct {
+	code Code
+	key  string
+}
+
+func newThrottle(interval time.Duration) *throttle {
+	return &throttle{
+		interval: interval,
+		seen:     make(map[throttleKey]time.Time),
+	}
+}
+
+// allow checks if the key may be reported again, removing any keys last
+// reported before the interval at most once per interval.
+func (th *throttle) allow(code Code, key string, now time.Time) bool {
+	th.mu.Lock()
+	defer th.mu.Unlock()
+	if now.Sub(th.pruned) >= th.interval {
+		for k, t := range th.seen {
+			if now.Sub(t) >= th.interval {
+				delete(th.seen, k)
+			}
+		}
+		th.pruned = now
+	}
+	k := throttleKey{code, key}
+	if last, ok := th.seen[k]; ok && now.Sub(last) < th.interval {
+		return false
+	}
+	th.seen[k] = now
+	return true
+}
@@ -0,0 +1,31 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThrottle(t *testing.T) {
+	th := newThrottle(time.Minute)
+	now := time.Now()
+
+	assert.True(t, th.allow("en", "foo", now))
+	assert.False(t, th.allow("en", "foo", now.Add(time.Second)))
+	assert.True(t, th.allow("es", "foo", now.Add(time.Second)))
+	assert.True(t, th.allow("en", "bar", now.Add(2*time.Second)))
+	assert.Len(t, th.seen, 3)
+
+	later := now.Add(time.Minute + 10*time.Second)
+	assert.True(t, th.allow("en", "baz", later))
+	assert.Len(t, th.seen, 1, "old keys removed")
+	assert.True(t, th.allow("en", "foo", later))
+
+	t.Run("no interval", func(t *testing.T) {
+		th := newThrottle(0)
+		assert.True(t, th.allow("en", "foo", now))
+		assert.True(t, th.allow("en", "foo", now))
+		assert.Len(t, th.seen, 1)
+	})
+}
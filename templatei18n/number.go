@@ -0,0 +1,85 @@
+package templatei18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+// Keys used to look up the symbols used by the `number` function, following
+// the same convention as Ruby on Rails. English symbols are used when the
+// keys are not defined in the locale.
+const (
+	numberSeparatorKey = "number.format.separator"
+	numberDelimiterKey = "number.format.delimiter"
+)
+
+// number formats the value with the locale's decimal separator and
+// thousands delimiter, using the precision if provided.
+func number(ctx context.Context, v any, precision ...int) (string, error) {
+	if len(precision) > 1 {
+		return "", fmt.Errorf("number: expected at most one precision, got %d", len(precision))
+	}
+	p := -1
+	if len(precision) == 1 {
+		p = precision[0]
+	}
+
+	var s string
+	switch n := v.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s = fmt.Sprint(n)
+		if p > 0 {
+			s += "." + strings.Repeat("0", p)
+		}
+	case float32:
+		s = strconv.FormatFloat(float64(n), 'f', p, 32)
+	case float64:
+		s = strconv.FormatFloat(n, 'f', p, 64)
+	case json.Number:
+		f, err := n.Float64()
+		if err != nil {
+			return "", fmt.Errorf("number: %w", err)
+		}
+		s = strconv.FormatFloat(f, 'f', p, 64)
+	default:
+		return "", fmt.Errorf("number: unsupported value: %v", v)
+	}
+
+	sep, del := ".", ","
+	if l := i18n.GetLocale(ctx); l != nil {
+		if l.Has(numberSeparatorKey) {
+			sep = l.T(numberSeparatorKey)
+		}
+		if l.Has(numberDelimiterKey) {
+			del = l.T(numberDelimiterKey)
+		}
+	}
+	return localizeNumber(s, sep, del), nil
+}
+
+// localizeNumber replaces the symbols in a number formatted by strconv.
+func localizeNumber(s, sep, del string) string {
+	sign := ""
+	if strings.HasPrefix(s, "-") {
+		sign, s = "-", s[1:]
+	}
+	whole, frac, _ := strings.Cut(s, ".")
+	var b strings.Builder
+	b.WriteString(sign)
+	for i, r := range whole {
+		if i > 0 && (len(whole)-i)%3 == 0 {
+			b.WriteString(del)
+		}
+		b.WriteRune(r)
+	}
+	if frac != "" {
+		b.WriteString(sep)
+		b.WriteString(frac)
+	}
+	return b.String()
+}
@@ -0,0 +1,184 @@
+// Package templatei18n provides functions to use translations inside
+// `text/template` and `html/template` templates, which have no access to
+// the context otherwise.
+//
+// Templates may then use the functions like:
+//
+//	<h1>{{ t "welcome.title" }}</h1>
+//	<p>{{ t "welcome.hello" (m "name" .Name) }}</p>
+//	<p>{{ n "inbox.emails" .Count (m "count" .Count) }}</p>
+//	<p>{{ t "welcome.since" .Year }}</p>
+//	{{ if has "welcome.tagline" }}<p>{{ t "welcome.tagline" }}</p>{{ end }}
+//	<p>{{ number .Total 2 }}</p>
+package templatei18n
+
+import (
+	"context"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+// htmlSuffix is used at the end of keys whose translations contain HTML,
+// following the same convention as Ruby on Rails.
+const htmlSuffix = "html"
+
+// FuncMap provides the functions for `text/template` that use the locale
+// in the context:
+//
+//   - `t`: translates the key with optional arguments.
+//   - `n`: translates the key pluralized using the count.
+//   - `has`: checks if the key is defined.
+//   - `list`: provides the list of texts defined for the key.
+//   - `int`, `float` and `bool`: provide the typed value defined for the key.
+//   - `number`: formats a number with the locale's `number.format.separator`
+//     and `number.format.delimiter`, and an optional precision.
+//   - `m`: prepares an `i18n.M` from pairs of keys and values.
+//   - `lang` and `dir`: provide the locale's code and text direction.
+//
+// A single map argument, usually prepared with `m`, is used to replace the
+// `%{name}` placeholders, while any other arguments are passed as they are
+// to `fmt` style texts.
+func FuncMap(ctx context.Context) texttemplate.FuncMap {
+	return texttemplate.FuncMap(funcs(ctx, false))
+}
+
+// HTMLFuncMap provides the same functions as FuncMap for `html/template`.
+// Translations are escaped by the template as usual, apart from those with
+// keys ending in `_html` or `.html`, which are expected to contain trusted
+// markup and are returned as `template.HTML` with any interpolated values
+// escaped.
+func HTMLFuncMap(ctx context.Context) htmltemplate.FuncMap {
+	return htmltemplate.FuncMap(funcs(ctx, true))
+}
+
+// LocaleFuncMap provides the FuncMap functions bound to the locale.
+func LocaleFuncMap(l *i18n.Locale) texttemplate.FuncMap {
+	return FuncMap(l.WithContext(context.Background()))
+}
+
+// LocaleHTMLFuncMap provides the HTMLFuncMap functions bound to the
+// locale.
+func LocaleHTMLFuncMap(l *i18n.Locale) htmltemplate.FuncMap {
+	return HTMLFuncMap(l.WithContext(context.Background()))
+}
+
+func funcs(ctx context.Context, safe bool) map[string]any {
+	t := func(key string, args ...any) string {
+		return i18n.T(ctx, key, prepareArgs(args)...)
+	}
+	n := func(key string, count int, args ...any) string {
+		return i18n.N(ctx, key, count, prepareArgs(args)...)
+	}
+	fm := map[string]any{
+		"t":   t,
+		"n":   n,
+		"has": func(key string) bool { return i18n.Has(ctx, key) },
+		"list": func(key string) []string {
+			return i18n.List(ctx, key)
+		},
+		"int":   func(key string) int { return i18n.Int(ctx, key) },
+		"float": func(key string) float64 { return i18n.Float(ctx, key) },
+		"bool":  func(key string) bool { return i18n.Bool(ctx, key) },
+		"number": func(v any, precision ...int) (string, error) {
+			return number(ctx, v, precision...)
+		},
+		"m":    pairs,
+		"lang": func() string { return lang(ctx).String() },
+		"dir":  func() string { return lang(ctx).Direction() },
+	}
+	if safe {
+		fm["t"] = func(key string, args ...any) any {
+			if !isHTMLKey(key) {
+				return t(key, args...)
+			}
+			return htmltemplate.HTML(t(key, escapeArgs(args)...))
+		}
+		fm["n"] = func(key string, count int, args ...any) any {
+			if !isHTMLKey(key) {
+				return n(key, count, args...)
+			}
+			return htmltemplate.HTML(n(key, count, escapeArgs(args)...))
+		}
+	}
+	return fm
+}
+
+func lang(ctx context.Context) i18n.Code {
+	l := i18n.GetLocale(ctx)
+	if l == nil {
+		return ""
+	}
+	return l.Code()
+}
+
+// isHTMLKey checks if the key follows the convention for translations
+// containing HTML.
+func isHTMLKey(key string) bool {
+	return strings.HasSuffix(key, "_"+htmlSuffix) || strings.HasSuffix(key, "."+htmlSuffix)
+}
+
+// prepareArgs converts a single map argument into an `i18n.M` so it may
+// be used for interpolation.
+func prepareArgs(args []any) []any {
+	if len(args) == 1 {
+		if v, ok := args[0].(map[string]any); ok {
+			return []any{i18n.M(v)}
+		}
+	}
+	return args
+}
+
+// pairs prepares a map from pairs of names and values.
+func pairs(args ...any) (i18n.M, error) {
+	if len(args)%2 != 0 {
+		return nil, fmt.Errorf("m: expected pairs of names and values, got %d arguments", len(args))
+	}
+	m := make(i18n.M, len(args)/2)
+	for i := 0; i < len(args); i += 2 {
+		k, ok := args[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("m: name must be a string: %v", args[i])
+		}
+		m[k] = args[i+1]
+	}
+	return m, nil
+}
+
+// escapeArgs escapes any values that will be inserted into an HTML
+// translation, apart from those already marked as safe.
+func escapeArgs(args []any) []any {
+	args = prepareArgs(args)
+	out := make([]any, len(args))
+	for i, a := range args {
+		switch v := a.(type) {
+		case i18n.M:
+			m := make(i18n.M, len(v))
+			for k, val := range v {
+				m[k] = escape(val)
+			}
+			out[i] = m
+		case i18n.DefaultText:
+			out[i] = v
+		default:
+			out[i] = escape(v)
+		}
+	}
+	return out
+}
+
+func escape(v any) any {
+	switch s := v.(type) {
+	case htmltemplate.HTML:
+		return string(s)
+	case string:
+		return htmltemplate.HTMLEscapeString(s)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool:
+		return v
+	default:
+		return htmltemplate.HTMLEscaper(v)
+	}
+}
@@ -0,0 +1,162 @@
+package templatei18n_test
+
+import (
+	"bytes"
+	"context"
+	htmltemplate "html/template"
+	"testing"
+	texttemplate "text/template"
+
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/templatei18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func locale(t *testing.T) *i18n.Locale {
+	t.Helper()
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.UnmarshalJSON([]byte(`{
+		"en": {
+			"welcome": {
+				"title": "Welcome <friends>",
+				"hello": "Hello, %{name}",
+				"intro_html": "Hello, <strong>%{name}</strong>",
+				"html": "<em>%d</em> new",
+				"greet": "Hello %s %s",
+				"greet_html": "Hello <b>%s</b> %s"
+			},
+			"inbox": {
+				"emails": {"one": "One email", "other": "%{count} emails"},
+				"emails_html": {"one": "<b>One</b> email", "other": "<b>%{count}</b> emails"}
+			},
+			"days": ["Mon", "Tue"]
+		},
+		"he": {"welcome": {"title": "שלום"}}
+	}`)))
+	return ls.Get("en")
+}
+
+type data struct {
+	Name  string
+	Last  string
+	Safe  htmltemplate.HTML
+	Count int
+	Map   map[string]any
+}
+
+func TestFuncMap(t *testing.T) {
+	l := locale(t)
+	tmpl := texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(l)).Parse(
+		`{{ t "welcome.title" }}|{{ t "welcome.hello" .Map }}|` +
+			`{{ t "welcome.hello" (m "name" .Name) }}|{{ n "inbox.emails" .Count (m "count" .Count) }}|` +
+			`{{ has "welcome.title" }} {{ has "bad" }}|{{ range list "days" }}{{ . }} {{ end }}|` +
+			`{{ lang }} {{ dir }}|{{ t "welcome.html" 3 }}|{{ t "bad" }}`,
+	))
+	out := new(bytes.Buffer)
+	require.NoError(t, tmpl.Execute(out, data{Name: "<Sam>", Count: 2, Map: map[string]any{"name": "<Sam>"}}))
+	assert.Equal(t, "Welcome <friends>|Hello, <Sam>|Hello, <Sam>|2 emails|true false|Mon Tue |en ltr|<em>3</em> new|!(MISSING: bad)", out.String())
+}
+
+func TestHTMLFuncMap(t *testing.T) {
+	ctx := locale(t).WithContext(context.Background())
+	tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(templatei18n.HTMLFuncMap(ctx)).Parse(
+		`<h1>{{ t "welcome.title" }}</h1>` +
+			`<p>{{ t "welcome.hello" (m "name" .Name) }}</p>` +
+			`<p>{{ t "welcome.intro_html" (m "name" .Name) }}</p>` +
+			`<p>{{ t "welcome.intro_html" (m "name" .Safe) }}</p>` +
+			`<p>{{ n "inbox.emails_html" .Count (m "count" .Count) }}</p>` +
+			`<p>{{ n "inbox.emails" .Count (m "count" .Count) }}</p>` +
+			`<p>{{ t "welcome.html" 3 }}</p>` +
+			`<p title="{{ t "welcome.title" }}">{{ t "missing_html" }}</p>`,
+	))
+	out := new(bytes.Buffer)
+	require.NoError(t, tmpl.Execute(out, data{Name: "<Sam>", Safe: "<i>Sam</i>", Count: 2}))
+	assert.Equal(t,
+		`<h1>Welcome &lt;friends&gt;</h1>`+
+			`<p>Hello, &lt;Sam&gt;</p>`+
+			`<p>Hello, <strong>&lt;Sam&gt;</strong></p>`+
+			`<p>Hello, <strong><i>Sam</i></strong></p>`+
+			`<p><b>2</b> emails</p>`+
+			`<p>2 emails</p>`+
+			`<p><em>3</em> new</p>`+
+			`<p title="Welcome &lt;friends&gt;">!(MISSING: missing_html)</p>`,
+		out.String(),
+	)
+}
+
+func TestFuncMapArgs(t *testing.T) {
+	l := locale(t)
+	d := data{Name: "<Sam>", Last: "name"}
+
+	tmpl := texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(l)).Parse(
+		`{{ t "welcome.greet" .Name .Last }}|{{ t "welcome.greet" "name" "Sam" }}`,
+	))
+	out := new(bytes.Buffer)
+	require.NoError(t, tmpl.Execute(out, d))
+	assert.Equal(t, "Hello <Sam> name|Hello name Sam", out.String())
+
+	htmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(templatei18n.LocaleHTMLFuncMap(l)).Parse(
+		`<p>{{ t "welcome.greet" .Name .Last }}</p><p>{{ t "welcome.greet_html" .Name .Last }}</p>`,
+	))
+	out = new(bytes.Buffer)
+	require.NoError(t, htmpl.Execute(out, d))
+	assert.Equal(t, `<p>Hello &lt;Sam&gt; name</p><p>Hello <b>&lt;Sam&gt;</b> name</p>`, out.String())
+
+	t.Run("invalid pairs", func(t *testing.T) {
+		tmpl := texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(l)).Parse(
+			`{{ t "welcome.hello" (m "name") }}`,
+		))
+		err := tmpl.Execute(new(bytes.Buffer), d)
+		assert.ErrorContains(t, err, "m: expected pairs of names and values, got 1 arguments")
+
+		tmpl = texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(l)).Parse(
+			`{{ t "welcome.hello" (m 1 "Sam") }}`,
+		))
+		err = tmpl.Execute(new(bytes.Buffer), d)
+		assert.ErrorContains(t, err, "m: name must be a string: 1")
+	})
+}
+
+func TestLocaleHTMLFuncMap(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.UnmarshalJSON([]byte(`{"he": {"title": "שלום"}}`)))
+	fm := templatei18n.LocaleHTMLFuncMap(ls.Get("he"))
+	tmpl := htmltemplate.Must(htmltemplate.New("test").Funcs(fm).Parse(
+		`<html lang="{{ lang }}" dir="{{ dir }}">{{ t "title" }}</html>`,
+	))
+	out := new(bytes.Buffer)
+	require.NoError(t, tmpl.Execute(out, nil))
+	assert.Equal(t, `<html lang="he" dir="rtl">שלום</html>`, out.String())
+
+	fm = templatei18n.HTMLFuncMap(context.Background())
+	assert.Equal(t, "", fm["lang"].(func() string)())
+	assert.Equal(t, "!(MISSING LOCALE)", fm["t"].(func(string, ...any) any)("title"))
+}
+
+func TestFuncMapValues(t *testing.T) {
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.UnmarshalJSON([]byte(`{
+		"en": {"limits": {"max": 5, "ratio": 0.25, "enabled": true}},
+		"de": {"number": {"format": {"separator": ",", "delimiter": "."}}}
+	}`)))
+	tmpl := texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(ls.Get("en"))).Parse(
+		`{{ int "limits.max" }} {{ float "limits.ratio" }} {{ bool "limits.enabled" }} {{ int "bad" }}|` +
+			`{{ number 1234567 }} {{ number -1234.5 }} {{ number 1234.5 2 }} {{ number 12 1 }} {{ number .Count }}`,
+	))
+	out := new(bytes.Buffer)
+	require.NoError(t, tmpl.Execute(out, data{Count: 1000}))
+	assert.Equal(t, "5 0.25 true 0|1,234,567 -1,234.5 1,234.50 12.0 1,000", out.String())
+
+	tmpl = texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(ls.Get("de"))).Parse(
+		`{{ number 1234567.891 2 }} {{ number 999 }}`,
+	))
+	out.Reset()
+	require.NoError(t, tmpl.Execute(out, nil))
+	assert.Equal(t, "1.234.567,89 999", out.String())
+
+	tmpl = texttemplate.Must(texttemplate.New("test").Funcs(templatei18n.LocaleFuncMap(ls.Get("de"))).Parse(
+		`{{ number "bad" }}`,
+	))
+	assert.ErrorContains(t, tmpl.Execute(out, nil), "number: unsupported value: bad")
+}
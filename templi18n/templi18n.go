@@ -0,0 +1,133 @@
+// Package templi18n provides components and helpers to use translations
+// inside [templ](https://templ.guide) files, using the locale from the
+// context passed to each component when rendering.
+//
+// Components implement the `templ.Component` interface without this
+// package depending on templ itself, so they may be used with any version:
+//
+//	<h1>@templi18n.T("welcome.title")</h1>
+//	<p>@templi18n.N("inbox.emails", count, i18n.M{"count": count})</p>
+//	<html { templi18n.Attrs(ctx)... }>
+package templi18n
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"html"
+	"io"
+
+	"github.com/invopop/ctxi18n/i18n"
+)
+
+// Component is the interface implemented by `templ.Component`, which
+// all the components in this package satisfy.
+type Component interface {
+	// Render writes the component's HTML to the writer.
+	Render(ctx context.Context, w io.Writer) error
+}
+
+// ComponentFunc converts a function into a Component.
+type ComponentFunc func(ctx context.Context, w io.Writer) error
+
+// Render calls the function.
+func (f ComponentFunc) Render(ctx context.Context, w io.Writer) error {
+	return f(ctx, w)
+}
+
+// T renders the escaped translation of the key.
+func T(key string, args ...any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return writeEscaped(w, i18n.T(ctx, key, args...))
+	})
+}
+
+// N renders the escaped pluralized translation of the key using n as the
+// count.
+func N(key string, n int, args ...any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		return writeEscaped(w, i18n.N(ctx, key, n, args...))
+	})
+}
+
+// Fallback renders the escaped translation of the key if defined in the
+// locale, or the fallback component otherwise.
+func Fallback(key string, fallback Component, args ...any) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if !i18n.Has(ctx, key) {
+			return fallback.Render(ctx, w)
+		}
+		return writeEscaped(w, i18n.T(ctx, key, args...))
+	})
+}
+
+// Rich renders the translation of the key as HTML, so that texts may
+// contain markup like `<strong>`. Translations must therefore come from a
+// trusted source. Interpolated values are escaped, except for Components,
+// which will be rendered in place, making it possible to insert links or
+// other elements:
+//
+//	@templi18n.Rich("terms.accept", i18n.M{"link": termsLink()})
+func Rich(key string, args i18n.M) Component {
+	return ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		m, err := richArgs(ctx, args)
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, i18n.T(ctx, key, m))
+		return err
+	})
+}
+
+// richArgs prepares the interpolation values, escaping texts and
+// rendering components.
+func richArgs(ctx context.Context, args i18n.M) (i18n.M, error) {
+	m := make(i18n.M, len(args))
+	for k, v := range args {
+		c, ok := v.(Component)
+		if !ok {
+			m[k] = html.EscapeString(fmt.Sprint(v))
+			continue
+		}
+		buf := new(bytes.Buffer)
+		if err := c.Render(ctx, buf); err != nil {
+			return nil, fmt.Errorf("rendering %s: %w", k, err)
+		}
+		m[k] = buf.String()
+	}
+	return m, nil
+}
+
+// Lang provides the code of the locale in the context, or an empty string
+// if there is no locale.
+func Lang(ctx context.Context) string {
+	l := i18n.GetLocale(ctx)
+	if l == nil {
+		return ""
+	}
+	return l.Code().String()
+}
+
+// Dir provides the text direction of the locale in the context, either
+// "ltr" or "rtl".
+func Dir(ctx context.Context) string {
+	return i18n.Code(Lang(ctx)).Direction()
+}
+
+// Attrs provides the `lang` and `dir` attributes for the locale in the
+// context, to be spread into an element like `<html>`. The map may be
+// used directly as `templ.Attributes`.
+func Attrs(ctx context.Context) map[string]any {
+	attrs := map[string]any{
+		"dir": Dir(ctx),
+	}
+	if lang := Lang(ctx); lang != "" {
+		attrs["lang"] = lang
+	}
+	return attrs
+}
+
+func writeEscaped(w io.Writer, s string) error {
+	_, err := io.WriteString(w, html.EscapeString(s))
+	return err
+}
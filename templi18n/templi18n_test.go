@@ -0,0 +1,136 @@
+package templi18n_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/a-h/templ"
+	"github.com/invopop/ctxi18n/i18n"
+	"github.com/invopop/ctxi18n/templi18n"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+// Components must be usable wherever templ expects one, and templ's own
+// components may be used as fallbacks.
+var (
+	_ templ.Component     = templi18n.T("")
+	_ templ.Component     = templi18n.N("", 0)
+	_ templ.Component     = templi18n.Rich("", nil)
+	_ templ.Component     = templi18n.Fallback("", templ.NopComponent)
+	_ templi18n.Component = templ.Raw("")
+)
+
+const catalog = `{
+	"en": {
+		"welcome": {"title": "Welcome <friends> & family", "hello": "Hello, %{name}"},
+		"inbox": {"emails": {"zero": "No emails", "one": "One email", "other": "%{count} emails"}},
+		"terms": {"accept": "I <strong>accept</strong> the %{link} for %{name}", "link": "terms"}
+	},
+	"ar": {
+		"welcome": {"title": "مرحبا", "hello": "مرحبا %{name}"}
+	}
+}`
+
+func locales(t *testing.T) *i18n.Locales {
+	t.Helper()
+	ls := new(i18n.Locales)
+	require.NoError(t, ls.UnmarshalJSON([]byte(catalog)))
+	return ls
+}
+
+// page composes the components in a similar way to the code generated
+// by templ.
+func page(ctx context.Context, w io.Writer) error {
+	link := templi18n.ComponentFunc(func(ctx context.Context, w io.Writer) error {
+		if _, err := io.WriteString(w, `<a href="/terms">`); err != nil {
+			return err
+		}
+		if err := templi18n.T("terms.link").Render(ctx, w); err != nil {
+			return err
+		}
+		_, err := io.WriteString(w, `</a>`)
+		return err
+	})
+	fallback := templi18n.ComponentFunc(func(_ context.Context, w io.Writer) error {
+		_, err := io.WriteString(w, "<em>fallback</em>")
+		return err
+	})
+	attrs := templi18n.Attrs(ctx)
+	parts := []any{
+		"<html lang=\"", attrs["lang"], "\" dir=\"", attrs["dir"], "\">\n<h1>",
+		templi18n.T("welcome.title"),
+		"</h1>\n<p>",
+		templi18n.T("welcome.hello", i18n.M{"name": "<Sam>"}),
+		"</p>\n<p>",
+		templi18n.N("inbox.emails", 0),
+		" / ",
+		templi18n.N("inbox.emails", 3, i18n.M{"count": 3}),
+		"</p>\n<p>",
+		templi18n.Fallback("welcome.title", fallback),
+		" / ",
+		templi18n.Fallback("welcome.missing", fallback),
+		"</p>\n<p>",
+		templi18n.Rich("terms.accept", i18n.M{"link": link, "name": "<Sam>"}),
+		"</p>\n</html>\n",
+	}
+	for _, p := range parts {
+		var err error
+		switch v := p.(type) {
+		case templi18n.Component:
+			err = v.Render(ctx, w)
+		default:
+			_, err = io.WriteString(w, v.(string))
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func TestRender(t *testing.T) {
+	ls := locales(t)
+	for _, code := range []i18n.Code{"en", "ar"} {
+		t.Run(code.String(), func(t *testing.T) {
+			ctx := ls.Get(code).WithContext(context.Background())
+			out := new(bytes.Buffer)
+			require.NoError(t, page(ctx, out))
+
+			golden := filepath.Join("testdata", code.String()+".golden")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, out.Bytes(), 0o644))
+			}
+			data, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), out.String())
+		})
+	}
+}
+
+func TestRichError(t *testing.T) {
+	ctx := locales(t).Get("en").WithContext(context.Background())
+	bad := templi18n.ComponentFunc(func(_ context.Context, _ io.Writer) error {
+		return errors.New("broken")
+	})
+	err := templi18n.Rich("terms.accept", i18n.M{"link": bad}).Render(ctx, io.Discard)
+	assert.EqualError(t, err, "rendering link: broken")
+}
+
+func TestAttrs(t *testing.T) {
+	ctx := context.Background()
+	assert.Equal(t, "", templi18n.Lang(ctx))
+	assert.Equal(t, "ltr", templi18n.Dir(ctx))
+	assert.Equal(t, map[string]any{"dir": "ltr"}, templi18n.Attrs(ctx))
+
+	ctx = locales(t).Get("ar").WithContext(ctx)
+	assert.Equal(t, map[string]any{"lang": "ar", "dir": "rtl"}, templi18n.Attrs(ctx))
+}